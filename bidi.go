@@ -0,0 +1,471 @@
+package texttable
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Direction sets the base direction of the paragraphs of a text,
+// which decides the order of its bidirectional runs and the side
+// it is aligned to by AlignStart.
+type Direction int
+
+const (
+	// DirectionAuto takes the direction of each paragraph from
+	// its first letter with a strong direction, as set out by the
+	// Unicode Bidirectional Algorithm. Paragraphs without such a
+	// letter are left-to-right.
+	DirectionAuto Direction = iota
+	// DirectionLeftToRight makes every paragraph left-to-right.
+	DirectionLeftToRight
+	// DirectionRightToLeft makes every paragraph right-to-left.
+	DirectionRightToLeft
+)
+
+// bidiClass is the bidirectional character type of a
+// character (UAX #9). The explicit embedding and isolate
+// formatting characters are not supported, and are treated
+// as boundary neutrals.
+type bidiClass int
+
+const (
+	bidiL   bidiClass = iota // left-to-right
+	bidiR                    // right-to-left
+	bidiAL                   // Arabic letter
+	bidiEN                   // European number
+	bidiES                   // European separator
+	bidiET                   // European terminator
+	bidiAN                   // Arabic number
+	bidiCS                   // common separator
+	bidiNSM                  // non-spacing mark
+	bidiBN                   // boundary neutral
+	bidiWS                   // white space
+	bidiON                   // other neutral
+)
+
+// bidiClassOf returns the bidirectional type of a rune.
+func bidiClassOf(r rune) bidiClass {
+	switch {
+	case r < 0x80:
+		switch {
+		case '0' <= r && r <= '9':
+			return bidiEN
+		case r == '+' || r == '-':
+			return bidiES
+		case r == '#' || r == '$' || r == '%':
+			return bidiET
+		case r == ',' || r == '.' || r == '/' || r == ':':
+			return bidiCS
+		case r == ' ' || r == '\t' || r == '\f':
+			return bidiWS
+		case unicode.IsLetter(r):
+			return bidiL
+		case r < 0x20 || r == 0x7f:
+			return bidiBN
+		}
+		return bidiON
+	case r == '\u200e':
+		return bidiL
+	case r == '\u200f':
+		return bidiR
+	case r == '\u061c':
+		return bidiAL
+	case r == '\u00ad', '\u200b' <= r && r <= '\u200d', '\u202a' <= r && r <= '\u202e',
+		'\u2066' <= r && r <= '\u2069', r == '\ufeff':
+		return bidiBN
+	case unicode.In(r, unicode.Mn, unicode.Me):
+		return bidiNSM
+	case '\u0660' <= r && r <= '\u0669', r == '\u066b', r == '\u066c',
+		'\u0600' <= r && r <= '\u0605', r == '\u06dd':
+		return bidiAN
+	case '\u06f0' <= r && r <= '\u06f9', '\u2070' <= r && r <= '\u2079',
+		'\u2080' <= r && r <= '\u2089', '\uff10' <= r && r <= '\uff19',
+		r == '\u00b2', r == '\u00b3', r == '\u00b9':
+		return bidiEN
+	case r == '\u2212', r == '\u207a', r == '\u207b', r == '\uff0b', r == '\uff0d':
+		return bidiES
+	case '\u00a2' <= r && r <= '\u00a5', r == '\u00b0', r == '\u00b1', r == '\u066a',
+		'\u2030' <= r && r <= '\u2034', '\u20a0' <= r && r <= '\u20cf':
+		return bidiET
+	case r == '\u00a0', r == '\u060c', r == '\u202f', r == '\u2044', r == '\uff0c',
+		r == '\uff0e', r == '\uff0f', r == '\uff1a':
+		return bidiCS
+	case unicode.Is(unicode.Zs, r):
+		return bidiWS
+	case '\u0590' <= r && r <= '\u05ff', '\u07c0' <= r && r <= '\u085f',
+		'\ufb1d' <= r && r <= '\ufb4f', '\U00010800' <= r && r <= '\U00010fff',
+		'\U0001e800' <= r && r <= '\U0001edff':
+		return bidiR
+	case '\u0600' <= r && r <= '\u07bf', '\u0860' <= r && r <= '\u08ff',
+		'\ufb50' <= r && r <= '\ufdff', '\ufe70' <= r && r <= '\ufeff',
+		'\U0001ee00' <= r && r <= '\U0001eeff':
+		return bidiAL
+	case unicode.In(r, unicode.L, unicode.Mc, unicode.N):
+		return bidiL
+	}
+	return bidiON
+}
+
+// isRightToLeft reports whether a bidirectional
+// type is a strong right-to-left one.
+func isRightToLeft(class bidiClass) bool {
+	return class == bidiR || class == bidiAL
+}
+
+// hasRightToLeft reports whether any character of the text
+// can change the order of its line.
+func hasRightToLeft(text string) bool {
+	for _, r := range text {
+		if r >= 0x590 {
+			if class := bidiClassOf(r); isRightToLeft(class) || class == bidiAN {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// paragraphRightToLeft reports whether the first letter of the
+// paragraph with a strong direction is right-to-left (P2, P3).
+func paragraphRightToLeft(paragraph string) bool {
+	for _, r := range paragraph {
+		switch class := bidiClassOf(r); {
+		case class == bidiL:
+			return false
+		case isRightToLeft(class):
+			return true
+		}
+	}
+	return false
+}
+
+// paragraphDirections returns whether each paragraph of a text,
+// separated by new lines unless they are ignored, is right-to-left.
+// It returns nil if no paragraph is.
+func paragraphDirections(text string, ignoreNewLines bool) []bool {
+	if !hasRightToLeft(text) {
+		return nil
+	}
+	if ignoreNewLines {
+		return []bool{paragraphRightToLeft(text)}
+	}
+	paragraphs := strings.Split(text, "\n")
+	directions := make([]bool, len(paragraphs))
+	for i, paragraph := range paragraphs {
+		directions[i] = paragraphRightToLeft(paragraph)
+	}
+	return directions
+}
+
+// paragraphRTL reports whether the paragraph at the given
+// index of the text of the LineScanner is right-to-left.
+func (ls *LineScanner) paragraphRTL(paragraph int) bool {
+	switch ls.direction {
+	case DirectionLeftToRight:
+		return false
+	case DirectionRightToLeft:
+		return true
+	}
+	return paragraph < len(ls.directions) && ls.directions[paragraph]
+}
+
+// reorderLine returns a line of a paragraph in visual order,
+// following the Unicode Bidirectional Algorithm for text without
+// explicit embeddings. Combining marks are kept after the letters
+// they follow, and mirrored characters, such as brackets, are
+// mirrored in right-to-left runs.
+func reorderLine(line string, rtl bool) string {
+	if !rtl && !hasRightToLeft(line) {
+		return line
+	}
+	runes := []rune(line)
+	levels := resolveLevels(runes, rtl)
+
+	// L2: reverse every run at each level, from the highest
+	// level down to the lowest odd level.
+	highest, lowest := 0, 2
+	for _, level := range levels {
+		if level > highest {
+			highest = level
+		}
+		if level < lowest {
+			lowest = level
+		}
+	}
+	lowestOdd := lowest | 1
+	order := make([]int, len(runes))
+	for i := range order {
+		order[i] = i
+	}
+	for level := highest; level >= lowestOdd; level-- {
+		for i := 0; i < len(order); {
+			if levels[order[i]] < level {
+				i++
+				continue
+			}
+			j := i
+			for j < len(order) && levels[order[j]] >= level {
+				j++
+			}
+			for a, b := i, j-1; a < b; a, b = a+1, b-1 {
+				order[a], order[b] = order[b], order[a]
+			}
+			i = j
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(order); i++ {
+		// L3: a reversed combining sequence has its marks
+		// first, so the marks are moved back after the letter.
+		j := i
+		for j < len(order)-1 && levels[order[j]]%2 == 1 && unicode.In(runes[order[j]], unicode.Mn, unicode.Me) {
+			j++
+		}
+		for k := j; k >= i; k-- {
+			r := runes[order[k]]
+			if levels[order[k]]%2 == 1 {
+				r = mirror(r)
+			}
+			b.WriteRune(r)
+		}
+		i = j
+	}
+	return b.String()
+}
+
+// resolveLevels returns the embedding level of each character
+// of a line, applying the weak and neutral type rules and the
+// implicit levels of the Unicode Bidirectional Algorithm.
+func resolveLevels(runes []rune, rtl bool) []int {
+	base, sos := 0, bidiL
+	if rtl {
+		base, sos = 1, bidiR
+	}
+	classes := make([]bidiClass, len(runes))
+	for i, r := range runes {
+		classes[i] = bidiClassOf(r)
+	}
+	n := len(classes)
+
+	// W1: non-spacing marks take the type of the character
+	// before them. W2: European numbers after Arabic letters
+	// are Arabic numbers. W3: Arabic letters are right-to-left.
+	strong := sos
+	for i := range classes {
+		if classes[i] == bidiNSM {
+			classes[i] = sos
+			if i > 0 {
+				classes[i] = classes[i-1]
+			}
+		}
+		switch classes[i] {
+		case bidiL, bidiR, bidiAL:
+			strong = classes[i]
+		case bidiEN:
+			if strong == bidiAL {
+				classes[i] = bidiAN
+			}
+		}
+	}
+	for i := range classes {
+		if classes[i] == bidiAL {
+			classes[i] = bidiR
+		}
+	}
+
+	// W4: a single separator between two numbers of the same
+	// type joins them.
+	for i := 1; i < n-1; i++ {
+		before, after := classes[i-1], classes[i+1]
+		switch {
+		case classes[i] == bidiES && before == bidiEN && after == bidiEN:
+			classes[i] = bidiEN
+		case classes[i] == bidiCS && before == bidiEN && after == bidiEN:
+			classes[i] = bidiEN
+		case classes[i] == bidiCS && before == bidiAN && after == bidiAN:
+			classes[i] = bidiAN
+		}
+	}
+
+	// W5: terminators next to European numbers are part of them.
+	for i := 0; i < n; {
+		if classes[i] != bidiET {
+			i++
+			continue
+		}
+		j := i
+		for j < n && classes[j] == bidiET {
+			j++
+		}
+		if (i > 0 && classes[i-1] == bidiEN) || (j < n && classes[j] == bidiEN) {
+			for k := i; k < j; k++ {
+				classes[k] = bidiEN
+			}
+		}
+		i = j
+	}
+
+	// W6: remaining separators and terminators are neutral.
+	// W7: European numbers after left-to-right text are
+	// left-to-right.
+	strong = sos
+	for i, class := range classes {
+		switch class {
+		case bidiES, bidiET, bidiCS:
+			classes[i] = bidiON
+		case bidiL, bidiR:
+			strong = class
+		case bidiEN:
+			if strong == bidiL {
+				classes[i] = bidiL
+			}
+		}
+	}
+
+	// N0: paired brackets take the direction of the text
+	// between them, when it matches the direction of the
+	// paragraph or of the text before them.
+	for _, pair := range bracketPairs(runes, classes) {
+		if resolved, ok := resolveBrackets(classes, pair, sos); ok {
+			classes[pair[0]], classes[pair[1]] = resolved, resolved
+		}
+	}
+
+	// N1 and N2: neutrals between characters of the same
+	// direction take that direction, and otherwise the
+	// direction of the paragraph. Numbers count as
+	// right-to-left.
+	direction := func(i int) bidiClass {
+		if i < 0 || i >= n {
+			return sos
+		}
+		if classes[i] == bidiEN || classes[i] == bidiAN {
+			return bidiR
+		}
+		return classes[i]
+	}
+	for i := 0; i < n; {
+		if !isNeutral(classes[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < n && isNeutral(classes[j]) {
+			j++
+		}
+		resolved := sos
+		if before, after := direction(i-1), direction(j); before == after {
+			resolved = before
+		}
+		for k := i; k < j; k++ {
+			classes[k] = resolved
+		}
+		i = j
+	}
+
+	// I1 and I2: the implicit levels.
+	levels := make([]int, n)
+	for i, class := range classes {
+		switch {
+		case base == 0 && class == bidiR:
+			levels[i] = 1
+		case base == 0 && (class == bidiEN || class == bidiAN):
+			levels[i] = 2
+		case base == 1 && class != bidiR:
+			levels[i] = 2
+		default:
+			levels[i] = base
+		}
+	}
+
+	// L1: trailing white space is at the paragraph level.
+	for i := n - 1; i >= 0 && (bidiClassOf(runes[i]) == bidiWS || bidiClassOf(runes[i]) == bidiBN); i-- {
+		levels[i] = base
+	}
+	return levels
+}
+
+// bracketPairs returns the indexes of the opening and closing
+// brackets of a line which pair up (BD16), in the order of the
+// opening brackets.
+func bracketPairs(runes []rune, classes []bidiClass) [][2]int {
+	var pairs [][2]int
+	var stack []int
+	for i, r := range runes {
+		if classes[i] != bidiON {
+			continue
+		}
+		switch r {
+		case '(', '[', '{':
+			stack = append(stack, i)
+		case ')', ']', '}':
+			for j := len(stack) - 1; j >= 0; j-- {
+				if mirror(runes[stack[j]]) == r {
+					pairs = append(pairs, [2]int{stack[j], i})
+					stack = stack[:j]
+					break
+				}
+			}
+		}
+	}
+	sort.Slice(pairs, func(a, b int) bool {
+		return pairs[a][0] < pairs[b][0]
+	})
+	return pairs
+}
+
+// resolveBrackets returns the direction of a pair of brackets,
+// following rule N0. ok is false if they are left neutral.
+func resolveBrackets(classes []bidiClass, pair [2]int, sos bidiClass) (bidiClass, bool) {
+	strong := func(class bidiClass) bidiClass {
+		if class == bidiEN || class == bidiAN {
+			return bidiR
+		}
+		return class
+	}
+	opposite := false
+	for _, class := range classes[pair[0]+1 : pair[1]] {
+		switch strong(class) {
+		case sos:
+			return sos, true
+		case bidiL, bidiR:
+			opposite = true
+		}
+	}
+	if !opposite {
+		return 0, false
+	}
+	context := sos
+	for i := pair[0] - 1; i >= 0; i-- {
+		if class := strong(classes[i]); class == bidiL || class == bidiR {
+			context = class
+			break
+		}
+	}
+	return context, true
+}
+
+// isNeutral reports whether a resolved bidirectional
+// type is neutral.
+func isNeutral(class bidiClass) bool {
+	return class == bidiWS || class == bidiON || class == bidiBN
+}
+
+// mirrorPairs holds the characters which are mirrored
+// when displayed right-to-left, and their mirror images.
+var mirrorPairs = map[rune]rune{
+	'(': ')', ')': '(', '[': ']', ']': '[', '{': '}', '}': '{',
+	'<': '>', '>': '<', '\u00ab': '\u00bb', '\u00bb': '\u00ab',
+	'\u2039': '\u203a', '\u203a': '\u2039', '\u2045': '\u2046', '\u2046': '\u2045',
+	'\u2264': '\u2265', '\u2265': '\u2264', '\u3008': '\u3009', '\u3009': '\u3008',
+}
+
+// mirror returns the mirror image of a rune, if it has one.
+func mirror(r rune) rune {
+	if m, ok := mirrorPairs[r]; ok {
+		return m
+	}
+	return r
+}
@@ -0,0 +1,103 @@
+package texttable
+
+import (
+	"io"
+	"testing"
+)
+
+func TestReorderLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		rtl  bool
+		want string
+	}{
+		{name: "left-to-right", line: "hello world", want: "hello world"},
+		{name: "hebrew", line: "שלום עולם", rtl: true, want: "םלוע םולש"},
+		{name: "arabic with number", line: "مرحبا 123", rtl: true, want: "123 ابحرم"},
+		{name: "hebrew in english", line: "abc שלום עולם def", want: "abc םלוע םולש def"},
+		{name: "english in hebrew", line: "שלום abc def עולם", rtl: true, want: "םלוע abc def םולש"},
+		{name: "brackets", line: "שלום (abc) 12.5%", rtl: true, want: "12.5% (abc) םולש"},
+		{name: "mirrored brackets", line: "(שלום)", rtl: true, want: "(םולש)"},
+		{name: "combining marks", line: "בָּרוּךְ", rtl: true, want: "ךְוּרבָּ"},
+		{name: "english paragraph right-to-left", line: "abc def", rtl: true, want: "abc def"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reorderLine(tt.line, tt.rtl); got != tt.want {
+				t.Errorf("reorderLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLineScanner_Next_Direction(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		direction Direction
+		want      string
+	}{
+		{
+			name:  "detected paragraphs",
+			input: "hello\nשלום עולם רב\nabc",
+			want: `hello     
+ םלוע םולש
+        בר
+abc       
+`,
+		},
+		{
+			name:      "right-to-left",
+			input:     "hello",
+			direction: DirectionRightToLeft,
+			want: `     hello
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formattedText := ""
+			s := NewLineScanner(tt.input, LineScannerConfig{LineWidth: 10, Alignment: AlignStart, Direction: tt.direction})
+			for {
+				line, err := s.Next()
+				if err == io.EOF {
+					break
+				}
+				formattedText += line + "\n"
+			}
+			if formattedText != tt.want {
+				t.Fatalf("expected: \n%v\n\ngot: \n%v\n", tt.want, formattedText)
+			}
+		})
+	}
+}
+
+func TestTextTable_Output_RightToLeft(t *testing.T) {
+	input := [][]string{
+		{"id", "name"},
+		{"1", "דוד"},
+		{"2", "Alice"},
+	}
+	want := `     name        id 
+                    
+      דוד         1 
+                    
+    Alice         2 
+                    `
+
+	output, err := New(input, Config{
+		ColumnWidth:  8,
+		ColumnMargin: 1,
+		RowMargin:    1,
+		RightToLeft:  true,
+	}).Output()
+	if err != nil {
+		t.Fatalf("failed to format text table: %v", err)
+	}
+	if output != want {
+		t.Fatalf("expected: \n%v\n\ngot: \n%v\n", want, output)
+	}
+}
@@ -0,0 +1,332 @@
+package texttable
+
+import (
+	"strings"
+)
+
+// Change describes how a row differs between two tables.
+type Change int
+
+const (
+	// Unchanged rows are equal in both tables.
+	Unchanged Change = iota
+	// Added rows are only in the after table.
+	Added
+	// Removed rows are only in the before table.
+	Removed
+	// Changed rows are in both tables, with different values.
+	Changed
+)
+
+// changeMarkers are output in the first column of a diff.
+var changeMarkers = map[Change]string{
+	Unchanged: "",
+	Added:     "+",
+	Removed:   "-",
+	Changed:   "~",
+}
+
+// RowDiff holds a row of the before and after
+// tables, and how the row has changed. Before is
+// nil for added rows, and After for removed rows.
+type RowDiff struct {
+	Change Change
+	Before []string
+	After  []string
+}
+
+// DiffConfig should be used to set up the
+// comparison of two tables by the Diff function.
+type DiffConfig struct {
+	// KeyColumns holds the indexes of the columns which
+	// identify a row. Rows are matched by their position
+	// if it is empty.
+	KeyColumns []int
+	// HeaderRows is the number of rows at the top of the
+	// tables which hold headings. The header rows of the
+	// after table are output above the diff.
+	HeaderRows int
+	// ShowUnchanged includes the unchanged rows in the diff.
+	ShowUnchanged bool
+	// Table is used to output the diff, whose first column
+	// holds the change markers. Its column indexes refer to
+	// the columns of the compared tables, and are moved right
+	// past the marker column. Its HeaderRows are set from the
+	// header rows above.
+	Table Config
+
+	// AddedStyle, RemovedStyle and ChangedStyle are applied
+	// to added rows, removed rows and changed cells. They
+	// default to green, red and yellow text.
+	AddedStyle   Style
+	RemovedStyle Style
+	ChangedStyle Style
+}
+
+// changeArrow separates the before and after
+// values of a changed cell in a diff.
+const changeArrow = " → "
+
+// Compare matches the rows of two tables, either by the values
+// of their key columns or by their position, and reports how
+// each row has changed. The rows are returned in the order of
+// the after table, with removed rows placed after the row which
+// preceded them in the before table.
+func Compare(before, after [][]string, keyColumns []int) []RowDiff {
+	if len(keyColumns) == 0 {
+		return compareByPosition(before, after)
+	}
+
+	beforeIndexes := make(map[string]int)
+	for i, row := range before {
+		key := rowKey(row, keyColumns)
+		if _, ok := beforeIndexes[key]; !ok {
+			beforeIndexes[key] = i
+		}
+	}
+
+	// match the rows up front, so that rows
+	// which have moved are not reported as removed
+	matches := make([]int, len(after))
+	matched := make([]bool, len(before))
+	for j, row := range after {
+		i, ok := beforeIndexes[rowKey(row, keyColumns)]
+		if !ok || matched[i] {
+			matches[j] = -1
+			continue
+		}
+		matches[j] = i
+		matched[i] = true
+	}
+
+	// group the removed rows by the matched
+	// row which precedes them in the before table
+	removed := make(map[int][]RowDiff)
+	anchor := -1
+	for i, row := range before {
+		if matched[i] {
+			anchor = i
+			continue
+		}
+		removed[anchor] = append(removed[anchor], RowDiff{Change: Removed, Before: row})
+	}
+
+	diffs := removed[-1]
+	for j, row := range after {
+		i := matches[j]
+		if i < 0 {
+			diffs = append(diffs, RowDiff{Change: Added, After: row})
+			continue
+		}
+		diffs = append(diffs, compareRows(before[i], row))
+		diffs = append(diffs, removed[i]...)
+	}
+	return diffs
+}
+
+// Diff compares two tables with Compare and returns a TextTable
+// showing the added, removed and changed rows. Changed cells
+// show both values, each formatted by the Formatter of their
+// column and separated by an arrow, and are matched by the
+// rules on their value after the change.
+func Diff(before, after [][]string, config DiffConfig) *TextTable {
+	if config.HeaderRows < 0 {
+		config.HeaderRows = 0
+	}
+	if config.AddedStyle == (Style{}) {
+		config.AddedStyle = Style{Foreground: Green}
+	}
+	if config.RemovedStyle == (Style{}) {
+		config.RemovedStyle = Style{Foreground: Red}
+	}
+	if config.ChangedStyle == (Style{}) {
+		config.ChangedStyle = Style{Foreground: Yellow}
+	}
+
+	var header [][]string
+	if config.HeaderRows <= len(after) {
+		header, after = after[:config.HeaderRows], after[config.HeaderRows:]
+	}
+	if config.HeaderRows <= len(before) {
+		before = before[config.HeaderRows:]
+	}
+
+	tableConfig := shiftColumns(config.Table)
+	tableConfig.HeaderRows = len(header)
+	tableConfig.RowStyles = copyStyles(tableConfig.RowStyles)
+	tableConfig.ColumnWidths[0] = 1
+	tableConfig.cellTexts = make(map[Cell]string)
+
+	var textTable [][]string
+	for _, row := range header {
+		textTable = append(textTable, append([]string{""}, row...))
+	}
+	for _, diff := range Compare(before, after, config.KeyColumns) {
+		if diff.Change == Unchanged && !config.ShowUnchanged {
+			continue
+		}
+		y := len(textTable)
+		row := []string{changeMarkers[diff.Change]}
+		switch diff.Change {
+		case Unchanged:
+			row = append(row, diff.After...)
+		case Added:
+			row = append(row, diff.After...)
+			tableConfig.RowStyles[y] = tableConfig.RowStyles[y].merge(config.AddedStyle)
+		case Removed:
+			row = append(row, diff.Before...)
+			tableConfig.RowStyles[y] = tableConfig.RowStyles[y].merge(config.RemovedStyle)
+		case Changed:
+			for x := 0; x < len(diff.Before) || x < len(diff.After); x++ {
+				b, a := cellAt(diff.Before, x), cellAt(diff.After, x)
+				if a == b {
+					row = append(row, a)
+					continue
+				}
+				row = append(row, a)
+				cell := Cell{Row: y, Column: x + 1}
+				if format := tableConfig.Formatters[x+1]; format != nil {
+					b, a = format(b), format(a)
+				}
+				tableConfig.cellTexts[cell] = strings.TrimSpace(b + changeArrow + a)
+				tableConfig.CellStyles[cell] = tableConfig.CellStyles[cell].merge(config.ChangedStyle)
+			}
+		}
+		textTable = append(textTable, row)
+	}
+
+	return New(textTable, tableConfig)
+}
+
+// compareByPosition compares the rows of two
+// tables which are at the same position.
+func compareByPosition(before, after [][]string) []RowDiff {
+	var diffs []RowDiff
+	for i := 0; i < len(before) || i < len(after); i++ {
+		switch {
+		case i >= len(before):
+			diffs = append(diffs, RowDiff{Change: Added, After: after[i]})
+		case i >= len(after):
+			diffs = append(diffs, RowDiff{Change: Removed, Before: before[i]})
+		default:
+			diffs = append(diffs, compareRows(before[i], after[i]))
+		}
+	}
+	return diffs
+}
+
+// compareRows compares two matched rows.
+func compareRows(before, after []string) RowDiff {
+	change := Unchanged
+	for x := 0; x < len(before) || x < len(after); x++ {
+		if cellAt(before, x) != cellAt(after, x) {
+			change = Changed
+			break
+		}
+	}
+	return RowDiff{Change: change, Before: before, After: after}
+}
+
+// rowKey joins the values of the key columns of a row.
+func rowKey(row []string, keyColumns []int) string {
+	values := make([]string, len(keyColumns))
+	for i, x := range keyColumns {
+		values[i] = cellAt(row, x)
+	}
+	return strings.Join(values, "\x00")
+}
+
+// cellAt returns the value of a cell in a row, or an
+// empty string if the row does not reach the column.
+func cellAt(row []string, x int) string {
+	if x < 0 || x >= len(row) {
+		return ""
+	}
+	return row[x]
+}
+
+// shiftColumns returns a copy of the config with every column
+// index moved one column to the right, to make room for the
+// column of change markers.
+func shiftColumns(config Config) Config {
+	config.KeyColumns = shiftIndexes(config.KeyColumns)
+
+	columnWidths := make(map[int]int, len(config.ColumnWidths))
+	for x, width := range config.ColumnWidths {
+		columnWidths[x+1] = width
+	}
+	config.ColumnWidths = columnWidths
+
+	formatters := make(map[int]Formatter, len(config.Formatters))
+	for x, format := range config.Formatters {
+		formatters[x+1] = format
+	}
+	config.Formatters = formatters
+
+	alignments := make(map[int]Alignment, len(config.Alignments))
+	for x, alignment := range config.Alignments {
+		alignments[x+1] = alignment
+	}
+	config.Alignments = alignments
+
+	columnStyles := make(map[int]Style, len(config.ColumnStyles))
+	for x, style := range config.ColumnStyles {
+		columnStyles[x+1] = style
+	}
+	config.ColumnStyles = columnStyles
+
+	cellStyles := make(map[Cell]Style, len(config.CellStyles))
+	for cell, style := range config.CellStyles {
+		cellStyles[Cell{Row: cell.Row, Column: cell.Column + 1}] = style
+	}
+	config.CellStyles = cellStyles
+
+	spans := make(map[Cell]int, len(config.Spans))
+	for cell, span := range config.Spans {
+		spans[Cell{Row: cell.Row, Column: cell.Column + 1}] = span
+	}
+	config.Spans = spans
+
+	rules := make([]Rule, len(config.Rules))
+	for i, rule := range config.Rules {
+		rule.Columns = shiftIndexes(rule.Columns)
+		rules[i] = rule
+	}
+	config.Rules = rules
+
+	footers := make([]Footer, len(config.Footers))
+	for i, footer := range config.Footers {
+		footer.LabelColumn++
+		aggregates := make(map[int]Aggregate, len(footer.Aggregates))
+		for x, aggregate := range footer.Aggregates {
+			aggregates[x+1] = aggregate
+		}
+		footer.Aggregates = aggregates
+		footers[i] = footer
+	}
+	config.Footers = footers
+	return config
+}
+
+// shiftIndexes returns a copy of the
+// column indexes, each increased by one.
+func shiftIndexes(indexes []int) []int {
+	if indexes == nil {
+		return nil
+	}
+	shifted := make([]int, len(indexes))
+	for i, x := range indexes {
+		shifted[i] = x + 1
+	}
+	return shifted
+}
+
+// copyStyles returns a copy of a map of styles,
+// which is never nil.
+func copyStyles(styles map[int]Style) map[int]Style {
+	copied := make(map[int]Style, len(styles))
+	for k, style := range styles {
+		copied[k] = style
+	}
+	return copied
+}
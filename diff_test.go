@@ -0,0 +1,158 @@
+package texttable
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	before := [][]string{
+		{"1", "apple", "3"},
+		{"2", "pear", "5"},
+		{"3", "plum", "7"},
+	}
+	after := [][]string{
+		{"3", "plum", "7"},
+		{"1", "apple", "4"},
+		{"4", "fig", "1"},
+	}
+
+	tests := []struct {
+		name       string
+		keyColumns []int
+		want       []RowDiff
+	}{
+		{
+			name:       "by key",
+			keyColumns: []int{0},
+			want: []RowDiff{
+				{Change: Unchanged, Before: before[2], After: after[0]},
+				{Change: Changed, Before: before[0], After: after[1]},
+				{Change: Removed, Before: before[1]},
+				{Change: Added, After: after[2]},
+			},
+		},
+		{
+			name: "by position",
+			want: []RowDiff{
+				{Change: Changed, Before: before[0], After: after[0]},
+				{Change: Changed, Before: before[1], After: after[1]},
+				{Change: Changed, Before: before[2], After: after[2]},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compare(before, after, tt.keyColumns)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected: %+v, got: %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	before := [][]string{
+		{"id", "name", "qty"},
+		{"1", "apple", "3"},
+		{"2", "pear", "5"},
+		{"3", "plum", "7"},
+	}
+	after := [][]string{
+		{"id", "name", "qty"},
+		{"1", "apple", "4"},
+		{"3", "plum", "7"},
+		{"4", "fig", "1"},
+	}
+	want := `    id        name      qty      
+                                 
+ ~  1         apple     3 → 4    
+                                 
+ -  2         pear      5        
+                                 
+ +  4         fig       1        
+                                 `
+
+	output, err := Diff(before, after, DiffConfig{
+		KeyColumns: []int{0},
+		HeaderRows: 1,
+		Table: Config{
+			ColumnWidth:  8,
+			ColumnMargin: 1,
+			RowMargin:    1,
+		},
+	}).Output()
+	if err != nil {
+		t.Fatalf("failed to format text table: %v", err)
+	}
+
+	if output != want {
+		t.Fatalf("expected: \n%v\n\ngot: \n%v\n", want, output)
+	}
+}
+
+func TestDiff_ColumnOptions(t *testing.T) {
+	before := [][]string{
+		{"a", "1"},
+		{"b", "2"},
+	}
+	after := [][]string{
+		{"a", "2"},
+		{"b", "3"},
+	}
+	want := ` ~  a                1 → 2 
+                           
+ ~  b                2 → 3 
+                           `
+
+	output, err := Diff(before, after, DiffConfig{
+		KeyColumns: []int{0},
+		Table: Config{
+			ColumnWidth:  8,
+			ColumnMargin: 1,
+			RowMargin:    1,
+			Alignments:   map[int]Alignment{1: AlignRight},
+			ColumnWidths: map[int]int{1: 12},
+		},
+	}).Output()
+	if err != nil {
+		t.Fatalf("failed to format text table: %v", err)
+	}
+
+	if output != want {
+		t.Fatalf("expected: \n%v\n\ngot: \n%v\n", want, output)
+	}
+}
+
+func TestDiff_FormattersAndRules(t *testing.T) {
+	before := [][]string{
+		{"a", "1"},
+		{"b", "2"},
+	}
+	after := [][]string{
+		{"a", "-1.5"},
+		{"b", "2"},
+	}
+	want := " ~  a         \x1b[31m$1.00 →\x1b[0m  \n" +
+		"              \x1b[31m-$1.50\x1b[0m   \n" +
+		"                       "
+
+	output, err := Diff(before, after, DiffConfig{
+		KeyColumns: []int{0},
+		Table: Config{
+			ColumnWidth:  8,
+			ColumnMargin: 1,
+			Formatters:   map[int]Formatter{1: FormatCurrency("$", 2)},
+			Rules:        []Rule{NewRule(LessThan(0), Style{Foreground: Red}, 1)},
+			Color:        ColorAlways,
+		},
+	}).Output()
+	if err != nil {
+		t.Fatalf("failed to format text table: %v", err)
+	}
+
+	if output != want {
+		t.Fatalf("expected: \n%q\n\ngot: \n%q\n", want, output)
+	}
+}
@@ -0,0 +1,219 @@
+package texttable
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Footer describes a row which is output below the table,
+// whose cells are computed from the values of each column.
+type Footer struct {
+	// Label is output in the LabelColumn cell of the footer,
+	// unless that column is also aggregated.
+	Label       string
+	LabelColumn int
+	// Aggregates maps column indexes to the Aggregate
+	// used to compute the footer cell of that column.
+	Aggregates map[int]Aggregate
+	// Formatted sets whether the cells computed by the
+	// Aggregates are formatted by the Formatters of their
+	// columns. It should be set for aggregates whose results
+	// are in the units of their column, such as Sum, but not
+	// for counts.
+	Formatted bool
+}
+
+// Aggregate knows how to compute a single value from
+// the values of a table column. The values passed do not
+// include the header rows of the table.
+type Aggregate func(values []string) string
+
+// Sum is an Aggregate which adds up the numeric values
+// of a column. Values which are not numbers are ignored.
+// The sum is rounded to the largest number of decimal
+// places among the values.
+func Sum(values []string) string {
+	sum, _ := sumNumbers(values)
+	return formatNumber(sum)
+}
+
+// Avg is an Aggregate which computes the mean of the numeric
+// values of a column. Values which are not numbers are ignored.
+func Avg(values []string) string {
+	sum, count := sumNumbers(values)
+	if count == 0 {
+		return ""
+	}
+	// round away the error of the division,
+	// e.g. 0.3 / 3 = 0.09999999999999999
+	avg, _ := strconv.ParseFloat(strconv.FormatFloat(sum/float64(count), 'g', 15, 64), 64)
+	return formatNumber(avg)
+}
+
+// Min is an Aggregate which finds the smallest numeric value
+// of a column. Values which are not numbers are ignored.
+func Min(values []string) string {
+	numbers := parseNumbers(values)
+	if len(numbers) == 0 {
+		return ""
+	}
+	min := numbers[0]
+	for _, n := range numbers[1:] {
+		if n < min {
+			min = n
+		}
+	}
+	return formatNumber(min)
+}
+
+// Max is an Aggregate which finds the largest numeric value
+// of a column. Values which are not numbers are ignored.
+func Max(values []string) string {
+	numbers := parseNumbers(values)
+	if len(numbers) == 0 {
+		return ""
+	}
+	max := numbers[0]
+	for _, n := range numbers[1:] {
+		if n > max {
+			max = n
+		}
+	}
+	return formatNumber(max)
+}
+
+// Count is an Aggregate which counts the
+// non empty values of a column.
+func Count(values []string) string {
+	count := 0
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			count++
+		}
+	}
+	return strconv.Itoa(count)
+}
+
+// CountDistinct is an Aggregate which counts the
+// distinct non empty values of a column.
+func CountDistinct(values []string) string {
+	distinct := make(map[string]bool)
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			distinct[v] = true
+		}
+	}
+	return strconv.Itoa(len(distinct))
+}
+
+// makeFooterRows computes the cells of the configured footers
+// from the table values. The cells of Formatted footers are
+// formatted by the Formatter of their column.
+func (tf *TextTable) makeFooterRows() [][]string {
+	if len(tf.config.Footers) == 0 {
+		return nil
+	}
+	var width int
+	if len(tf.textTable) > 0 {
+		width = len(tf.textTable[0])
+	}
+	body := tf.textTable
+	if tf.config.HeaderRows < len(body) {
+		body = body[tf.config.HeaderRows:]
+	} else {
+		body = nil
+	}
+
+	footerRows := make([][]string, len(tf.config.Footers))
+	for i, footer := range tf.config.Footers {
+		footerRows[i] = make([]string, width)
+		if footer.LabelColumn >= 0 && footer.LabelColumn < width {
+			footerRows[i][footer.LabelColumn] = footer.Label
+		}
+		for x, aggregate := range footer.Aggregates {
+			if x < 0 || x >= width || aggregate == nil {
+				continue
+			}
+			values := make([]string, len(body))
+			for y := range body {
+				values[y] = body[y][x]
+			}
+			value := aggregate(values)
+			if format := tf.config.Formatters[x]; footer.Formatted && format != nil {
+				value = format(value)
+			}
+			footerRows[i][x] = value
+		}
+	}
+	return footerRows
+}
+
+// parseNumbers parses the values which are numbers,
+// allowing for surrounding white space and comma
+// thousands separators.
+func parseNumbers(values []string) []float64 {
+	numbers := make([]float64, 0, len(values))
+	for _, v := range values {
+		if n, ok := parseNumber(v); ok {
+			numbers = append(numbers, n)
+		}
+	}
+	return numbers
+}
+
+// parseNumber parses a single value as a number, allowing
+// for surrounding white space and comma thousands separators.
+func parseNumber(value string) (float64, bool) {
+	value = strings.Replace(strings.TrimSpace(value), ",", "", -1)
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// sumNumbers adds up the values which are numbers, and
+// returns the sum with the count of numbers. The sum is
+// rounded to the largest number of decimal places among
+// the numbers, so that errors of binary floating point,
+// e.g. 0.1 + 0.2 = 0.30000000000000004, are not kept.
+func sumNumbers(values []string) (sum float64, count int) {
+	places := 0
+	for _, v := range values {
+		n, ok := parseNumber(v)
+		if !ok {
+			continue
+		}
+		sum += n
+		count++
+		if d := decimalPlaces(v); d < 0 || places < 0 {
+			places = -1
+		} else if d > places {
+			places = d
+		}
+	}
+	if places < 0 {
+		return sum, count
+	}
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(sum, 'f', places, 64), 64)
+	return rounded, count
+}
+
+// decimalPlaces returns the number of decimal places of a
+// numeric value, or -1 if it is written with an exponent.
+func decimalPlaces(value string) int {
+	value = strings.TrimSpace(value)
+	if strings.ContainsAny(value, "eE") {
+		return -1
+	}
+	if i := strings.Index(value, "."); i >= 0 {
+		return len(value) - i - 1
+	}
+	return 0
+}
+
+// formatNumber formats a number using the fewest
+// digits needed to represent it.
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
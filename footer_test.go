@@ -0,0 +1,96 @@
+package texttable
+
+import (
+	"testing"
+)
+
+func TestAggregates(t *testing.T) {
+	values := []string{"3", " 1,500 ", "", "n/a", "-2.5", "3"}
+
+	tests := []struct {
+		name      string
+		aggregate Aggregate
+		want      string
+	}{
+		{name: "sum", aggregate: Sum, want: "1503.5"},
+		{name: "avg", aggregate: Avg, want: "375.875"},
+		{name: "min", aggregate: Min, want: "-2.5"},
+		{name: "max", aggregate: Max, want: "1500"},
+		{name: "count", aggregate: Count, want: "5"},
+		{name: "count distinct", aggregate: CountDistinct, want: "4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.aggregate(values); got != tt.want {
+				t.Fatalf("expected: %v, got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAggregates_Decimals(t *testing.T) {
+	tests := []struct {
+		name      string
+		aggregate Aggregate
+		values    []string
+		want      string
+	}{
+		{name: "sum", aggregate: Sum, values: []string{"0.1", "0.2"}, want: "0.3"},
+		{name: "sum of mixed places", aggregate: Sum, values: []string{"1.05", "2.1", "3"}, want: "6.15"},
+		{name: "sum with exponent", aggregate: Sum, values: []string{"1e-3", "2"}, want: "2.001"},
+		{name: "avg", aggregate: Avg, values: []string{"0.1", "0.2"}, want: "0.15"},
+		{name: "avg of division", aggregate: Avg, values: []string{"0.1", "0.1", "0.1"}, want: "0.1"},
+		{name: "avg of integers", aggregate: Avg, values: []string{"1", "2"}, want: "1.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.aggregate(tt.values); got != tt.want {
+				t.Fatalf("expected: %v, got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTextTableFormatter_Output_Footers(t *testing.T) {
+	input := [][]string{
+		{"item", "qty", "price"},
+		{"apple", "3", "1.5"},
+		{"pear", "5", "2"},
+		{"plum", "", "0.25"},
+	}
+	want := ` item    qty     price  
+                        
+ apple   3       1.5    
+                        
+ pear    5       2      
+                        
+ plum            0.25   
+                        
+------------------------
+ total   8       3.75   
+                        
+ count   2       3      
+                        `
+
+	ttf := New(input, Config{
+		ColumnWidth:  6,
+		ColumnMargin: 1,
+		RowMargin:    1,
+		HeaderRows:   1,
+		Footers: []Footer{
+			{Label: "total", Aggregates: map[int]Aggregate{1: Sum, 2: Sum}},
+			{Label: "count", Aggregates: map[int]Aggregate{1: Count, 2: Count}},
+		},
+	})
+
+	output, err := ttf.Output()
+	if err != nil {
+		t.Fatalf("failed to format text table: %v", err)
+	}
+
+	if output != want {
+		t.Fatalf("expected: \n%v\n\ngot: \n%v\n", want, output)
+	}
+}
@@ -0,0 +1,136 @@
+package texttable
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formatter knows how to format the raw value of a cell
+// before it is wrapped into lines. The formatters provided
+// by this package return values which they cannot parse
+// unchanged, so header cells are left as they are.
+type Formatter func(value string) string
+
+// byteSizeUnits are the binary units used by FormatByteSize.
+var byteSizeUnits = []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+// FormatThousands returns a Formatter which groups the integer
+// digits of numeric values with the given separator.
+// Example: 1234567.5 -> 1,234,567.5
+func FormatThousands(separator string) Formatter {
+	return func(value string) string {
+		n, ok := parseNumber(value)
+		if !ok {
+			return value
+		}
+		return groupThousands(formatNumber(n), separator)
+	}
+}
+
+// FormatDecimals returns a Formatter which rounds numeric
+// values to a fixed number of decimal places.
+// Example: 3.14159 -> 3.14
+func FormatDecimals(places int) Formatter {
+	return func(value string) string {
+		n, ok := parseNumber(value)
+		if !ok {
+			return value
+		}
+		return strconv.FormatFloat(n, 'f', places, 64)
+	}
+}
+
+// FormatCurrency returns a Formatter which prefixes numeric
+// values with a currency symbol, after rounding them to a
+// fixed number of decimal places and grouping their digits
+// with comma thousands separators.
+// Example: -1234.5 -> -$1,234.50
+func FormatCurrency(symbol string, places int) Formatter {
+	return func(value string) string {
+		n, ok := parseNumber(value)
+		if !ok {
+			return value
+		}
+		// round before taking the sign, so that
+		// values which round to zero are not negative
+		number := strconv.FormatFloat(n, 'f', places, 64)
+		sign := ""
+		if strings.HasPrefix(number, "-") {
+			number = number[1:]
+			if strings.Trim(number, "0.") != "" {
+				sign = "-"
+			}
+		}
+		return sign + symbol + groupThousands(number, ",")
+	}
+}
+
+// FormatPercent returns a Formatter which formats numeric
+// ratios as percentages with a fixed number of decimal places.
+// Example: 0.256 -> 25.6%
+func FormatPercent(places int) Formatter {
+	return func(value string) string {
+		n, ok := parseNumber(value)
+		if !ok {
+			return value
+		}
+		return strconv.FormatFloat(n*100, 'f', places, 64) + "%"
+	}
+}
+
+// FormatByteSize returns a Formatter which formats numeric
+// byte counts using binary units, with one decimal place.
+// Example: 1536 -> 1.5 KiB
+func FormatByteSize() Formatter {
+	return func(value string) string {
+		n, ok := parseNumber(value)
+		if !ok {
+			return value
+		}
+		if math.Abs(n) < 1024 {
+			return formatNumber(n) + " B"
+		}
+		unit := -1
+		for math.Abs(n) >= 1024 && unit < len(byteSizeUnits)-1 {
+			n /= 1024
+			unit++
+		}
+		return strconv.FormatFloat(n, 'f', 1, 64) + " " + byteSizeUnits[unit]
+	}
+}
+
+// FormatTime returns a Formatter which parses time values
+// using the input layout and formats them with the output
+// layout. The layouts are those used by the time package.
+func FormatTime(inputLayout, outputLayout string) Formatter {
+	return func(value string) string {
+		t, err := time.Parse(inputLayout, strings.TrimSpace(value))
+		if err != nil {
+			return value
+		}
+		return t.Format(outputLayout)
+	}
+}
+
+// groupThousands inserts the separator between every three
+// digits of the integer part of a formatted number.
+func groupThousands(number, separator string) string {
+	sign := ""
+	if strings.HasPrefix(number, "-") {
+		sign = "-"
+		number = number[1:]
+	}
+	integer, fraction := number, ""
+	if i := strings.IndexByte(number, '.'); i >= 0 {
+		integer, fraction = number[:i], number[i:]
+	}
+
+	grouped := ""
+	for len(integer) > 3 {
+		grouped = separator + integer[len(integer)-3:] + grouped
+		integer = integer[:len(integer)-3]
+	}
+	return sign + integer + grouped + fraction
+}
@@ -0,0 +1,108 @@
+package texttable
+
+import (
+	"testing"
+)
+
+func TestFormatters(t *testing.T) {
+	tests := []struct {
+		name      string
+		formatter Formatter
+		input     string
+		want      string
+	}{
+		{name: "thousands", formatter: FormatThousands(","), input: "1234567.5", want: "1,234,567.5"},
+		{name: "thousands negative", formatter: FormatThousands(" "), input: "-1234", want: "-1 234"},
+		{name: "thousands small", formatter: FormatThousands(","), input: "123", want: "123"},
+		{name: "decimals", formatter: FormatDecimals(2), input: "3.14159", want: "3.14"},
+		{name: "decimals padded", formatter: FormatDecimals(2), input: "3", want: "3.00"},
+		{name: "currency", formatter: FormatCurrency("$", 2), input: "-1234.5", want: "-$1,234.50"},
+		{name: "currency rounded to zero", formatter: FormatCurrency("$", 2), input: "-0.001", want: "$0.00"},
+		{name: "percent", formatter: FormatPercent(1), input: "0.256", want: "25.6%"},
+		{name: "bytes", formatter: FormatByteSize(), input: "512", want: "512 B"},
+		{name: "kibibytes", formatter: FormatByteSize(), input: "1536", want: "1.5 KiB"},
+		{name: "mebibytes", formatter: FormatByteSize(), input: "5242880", want: "5.0 MiB"},
+		{name: "time", formatter: FormatTime("2006-01-02", "02 Jan 2006"), input: "2019-12-17", want: "17 Dec 2019"},
+		{name: "not a number", formatter: FormatDecimals(2), input: "price", want: "price"},
+		{name: "not a time", formatter: FormatTime("2006-01-02", "02 Jan 2006"), input: "date", want: "date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.formatter(tt.input); got != tt.want {
+				t.Fatalf("expected: %v, got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTextTableFormatter_Output_Formatters(t *testing.T) {
+	input := [][]string{
+		{"item", "price"},
+		{"apple", "1234.5"},
+	}
+	want := ` item       price     
+                      
+ apple      $1,234.50 
+                      `
+
+	ttf := New(input, Config{
+		ColumnWidth:  9,
+		ColumnMargin: 1,
+		RowMargin:    1,
+		Formatters: map[int]Formatter{
+			1: FormatCurrency("$", 2),
+		},
+	})
+
+	output, err := ttf.Output()
+	if err != nil {
+		t.Fatalf("failed to format text table: %v", err)
+	}
+
+	if output != want {
+		t.Fatalf("expected: \n%v\n\ngot: \n%v\n", want, output)
+	}
+}
+
+func TestTextTableFormatter_Output_Formatters_HeadersAndFooters(t *testing.T) {
+	input := [][]string{
+		{"item", "2019"},
+		{"apple", "1.5"},
+		{"pear", "2"},
+	}
+	want := ` item      2019     
+                    
+ apple     $1.50    
+                    
+ pear      $2.00    
+                    
+--------------------
+ total     $3.50    
+                    
+ count     2        
+                    `
+
+	ttf := New(input, Config{
+		ColumnWidth:  8,
+		ColumnMargin: 1,
+		RowMargin:    1,
+		HeaderRows:   1,
+		Formatters: map[int]Formatter{
+			1: FormatCurrency("$", 2),
+		},
+		Footers: []Footer{
+			{Label: "total", Aggregates: map[int]Aggregate{1: Sum}, Formatted: true},
+			{Label: "count", Aggregates: map[int]Aggregate{1: Count}},
+		},
+	})
+
+	output, err := ttf.Output()
+	if err != nil {
+		t.Fatalf("failed to format text table: %v", err)
+	}
+
+	if output != want {
+		t.Fatalf("expected: \n%v\n\ngot: \n%v\n", want, output)
+	}
+}
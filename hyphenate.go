@@ -0,0 +1,205 @@
+package texttable
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// softHyphen marks where a word may be hyphenated. It is
+// not output unless the word is broken there.
+const softHyphen = "\u00ad"
+
+// The least number of letters left either side of
+// a hyphen, as in the defaults of TeX.
+const (
+	hyphenLeftMin  = 2
+	hyphenRightMin = 3
+)
+
+// Hyphenator knows how to find the points where a word can
+// be hyphenated, using Liang's pattern based algorithm.
+//
+// Hyphenator should only be constructed with the NewHyphenator
+// or EnglishHyphenator functions.
+type Hyphenator struct {
+	patterns   map[string][]int
+	exceptions map[string][]string
+	maxLength  int
+}
+
+var (
+	englishHyphenator     *Hyphenator
+	englishHyphenatorOnce sync.Once
+)
+
+// EnglishHyphenator returns a Hyphenator for US English,
+// built from the patterns embedded in the package.
+func EnglishHyphenator() *Hyphenator {
+	englishHyphenatorOnce.Do(func() {
+		englishHyphenator = NewHyphenator(englishPatterns, englishExceptions)
+	})
+	return englishHyphenator
+}
+
+// NewHyphenator knows how to create a new Hyphenator from
+// hyphenation patterns and exceptions, separated by white
+// space, in the format used by TeX. A pattern such as "hy3ph"
+// holds letters with digits between them, where an odd digit
+// allows a hyphen and an even digit forbids one. An exception
+// such as "ta-ble" gives the hyphens of a whole word.
+func NewHyphenator(patterns, exceptions string) *Hyphenator {
+	h := &Hyphenator{
+		patterns:   make(map[string][]int),
+		exceptions: make(map[string][]string),
+	}
+	for _, pattern := range strings.Fields(patterns) {
+		var letters []rune
+		points := []int{0}
+		for _, r := range pattern {
+			if '0' <= r && r <= '9' {
+				points[len(points)-1] = int(r - '0')
+				continue
+			}
+			letters = append(letters, r)
+			points = append(points, 0)
+		}
+		h.patterns[string(letters)] = points
+		if len(letters) > h.maxLength {
+			h.maxLength = len(letters)
+		}
+	}
+	for _, exception := range strings.Fields(exceptions) {
+		h.exceptions[strings.Replace(exception, "-", "", -1)] = strings.Split(exception, "-")
+	}
+	return h
+}
+
+// Hyphenate splits a word at the points where it can be
+// hyphenated. Any punctuation around the word is kept with
+// its first and last pieces. If the word holds soft hyphens,
+// it is split at them instead, and they are removed.
+func (h *Hyphenator) Hyphenate(word string) []string {
+	if strings.Contains(word, softHyphen) {
+		var pieces []string
+		for _, piece := range strings.Split(word, softHyphen) {
+			if piece != "" {
+				pieces = append(pieces, piece)
+			}
+		}
+		return pieces
+	}
+
+	start := strings.IndexFunc(word, unicode.IsLetter)
+	end := strings.LastIndexFunc(word, unicode.IsLetter)
+	if start < 0 {
+		return []string{word}
+	}
+	_, size := utf8.DecodeRuneInString(word[end:])
+	end += size
+
+	core := []rune(word[start:end])
+	lower := []rune(strings.ToLower(string(core)))
+	if len(core) < hyphenLeftMin+hyphenRightMin || len(lower) != len(core) {
+		return []string{word}
+	}
+	for _, r := range core {
+		if !unicode.IsLetter(r) {
+			return []string{word}
+		}
+	}
+
+	var breaks []int
+	if exception, ok := h.exceptions[string(lower)]; ok {
+		i := 0
+		for _, piece := range exception[:len(exception)-1] {
+			i += utf8.RuneCountInString(piece)
+			breaks = append(breaks, i)
+		}
+	} else {
+		breaks = h.breaks(lower)
+	}
+
+	pieces := make([]string, 0, len(breaks)+1)
+	i := 0
+	for _, b := range breaks {
+		pieces = append(pieces, string(core[i:b]))
+		i = b
+	}
+	pieces = append(pieces, string(core[i:]))
+	pieces[0] = word[:start] + pieces[0]
+	pieces[len(pieces)-1] += word[end:]
+	return pieces
+}
+
+// breaks returns the indexes of the letters of a lower
+// case word before which it can be hyphenated.
+func (h *Hyphenator) breaks(word []rune) []int {
+	// the word is surrounded with dots, which
+	// match the start and end of the patterns.
+	text := make([]rune, 0, len(word)+2)
+	text = append(append(append(text, '.'), word...), '.')
+	points := make([]int, len(text)+1)
+	for i := range text {
+		for j := i + 1; j <= len(text) && j-i <= h.maxLength; j++ {
+			pattern, ok := h.patterns[string(text[i:j])]
+			if !ok {
+				continue
+			}
+			for k, point := range pattern {
+				if point > points[i+k] {
+					points[i+k] = point
+				}
+			}
+		}
+	}
+
+	var breaks []int
+	for i := hyphenLeftMin; i <= len(word)-hyphenRightMin; i++ {
+		// the letter at i in the word is at
+		// i+1 in the text, after the dot.
+		if points[i+1]%2 == 1 {
+			breaks = append(breaks, i)
+		}
+	}
+	return breaks
+}
+
+// markHyphens marks the hyphenation points of a word with soft
+// hyphens, so that it can be broken at them like at any other
+// break opportunity. Words which already hold soft hyphens are
+// only hyphenated between them where a piece is too long to fit
+// on a line.
+func (ls *LineScanner) markHyphens(word string) string {
+	if ls.hyphenator == nil {
+		return word
+	}
+	if !strings.Contains(word, softHyphen) {
+		return ls.hyphenatePieces(word)
+	}
+	pieces := strings.Split(word, softHyphen)
+	for i, piece := range pieces {
+		if displayWidth(piece) > ls.lineWidth {
+			pieces[i] = ls.hyphenatePieces(piece)
+		}
+	}
+	return strings.Join(pieces, softHyphen)
+}
+
+// hyphenatePieces marks the hyphenation points of each piece
+// of a word between its break opportunities with soft hyphens.
+func (ls *LineScanner) hyphenatePieces(word string) string {
+	var b strings.Builder
+	start := 0
+	for _, end := range append(lineBreaks(word), len(word)) {
+		b.WriteString(strings.Join(ls.hyphenator.Hyphenate(word[start:end]), softHyphen))
+		start = end
+	}
+	return b.String()
+}
+
+// stripSoftHyphens removes the soft hyphens from a word.
+func stripSoftHyphens(word string) string {
+	return strings.Replace(word, softHyphen, "", -1)
+}
@@ -0,0 +1,103 @@
+package texttable
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestHyphenator_Hyphenate(t *testing.T) {
+	tests := []struct {
+		name string
+		word string
+		want []string
+	}{
+		{name: "patterns", word: "hyphenation", want: []string{"hy", "phen", "ation"}},
+		{name: "capitalised", word: "Algorithm", want: []string{"Al", "go", "rithm"}},
+		{name: "punctuation", word: "(extraordinary),", want: []string{"(ex", "traor", "di", "nary),"}},
+		{name: "exception", word: "table", want: []string{"ta", "ble"}},
+		{name: "exception without hyphens", word: "project", want: []string{"project"}},
+		{name: "short word", word: "the", want: []string{"the"}},
+		{name: "not a word", word: "12345678", want: []string{"12345678"}},
+		{name: "soft hyphens", word: "ab\u00adcdef\u00adgh", want: []string{"ab", "cdef", "gh"}},
+	}
+
+	h := EnglishHyphenator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := h.Hyphenate(tt.word); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Hyphenate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLineScanner_Next_Hyphenator(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		lineBreaking LineBreaking
+		want         string
+	}{
+		{
+			name:  "greedy",
+			input: "The hyphenation of extraordinarily long words",
+			want: `The hy-   
+phenation 
+of ex-    
+traordi-  
+narily    
+long words
+`,
+		},
+		{
+			name:         "optimal",
+			input:        "The hyphenation of extraordinarily long words",
+			lineBreaking: BreakOptimal,
+			want: `The       
+hyphen-   
+ation of  
+extraordi-
+narily    
+long words
+`,
+		},
+		{
+			name:  "soft hyphens",
+			input: "a super\u00adcali\u00adfragilistic word",
+			want: `a super-  
+califrag- 
+ilistic   
+word      
+`,
+		},
+		{
+			name:  "no hyphenation points",
+			input: "a 0123456789abc",
+			want: `a 01234567
+89abc     
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formattedText := ""
+			s := NewLineScanner(tt.input, LineScannerConfig{
+				LineWidth:    10,
+				LineBreaking: tt.lineBreaking,
+				Hyphenator:   EnglishHyphenator(),
+			})
+			for {
+				line, err := s.Next()
+				if err == io.EOF {
+					break
+				}
+				formattedText += line + "\n"
+			}
+			if formattedText != tt.want {
+				t.Fatalf("expected: \n%v\n\ngot: \n%v\n", tt.want, formattedText)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package texttable
+
+import (
+	"fmt"
+	"io"
+)
+
+// LineBreaking sets the algorithm a LineScanner
+// uses to choose where to break lines.
+type LineBreaking int
+
+const (
+	// BreakGreedy fills each line with as many
+	// words as fit before moving to the next.
+	BreakGreedy LineBreaking = iota
+	// BreakOptimal chooses the breaks of each paragraph
+	// which minimise its raggedness, measured as the sum
+	// of the squared space left at the end of every line
+	// but the last, in the style of Knuth and Plass.
+	BreakOptimal
+)
+
+// nextOptimal returns the next line in the text when the
+// optimal line breaking is used. The whole text is broken
+// into lines on the first call, as the breaks of a line
+// depend on the words which follow it.
+func (ls *LineScanner) nextOptimal() (string, error) {
+	if ls.lines == nil {
+		lines, err := ls.breakOptimal()
+		if err != nil {
+			return "", err
+		}
+		ls.lines = lines
+	}
+	if len(ls.lines) == 0 {
+		return "", io.EOF
+	}
+	line := ls.lines[0]
+	ls.lines = ls.lines[1:]
+	ls.rtl = ls.linesRTL[0]
+	ls.linesRTL = ls.linesRTL[1:]
+	return line, nil
+}
+
+// breakOptimal scans all the words of the text and
+// breaks each paragraph into lines optimally.
+func (ls *LineScanner) breakOptimal() ([]string, error) {
+	lines := make([]string, 0)
+	var paragraph []string
+	for ls.s.Scan() {
+		word := ls.s.Text()
+		if word == ls.newLineReturn {
+			lines = ls.appendParagraph(lines, paragraph)
+			paragraph = nil
+			continue
+		}
+		paragraph = append(paragraph, ls.splitLongWord(ls.markHyphens(word))...)
+	}
+	if err := ls.s.Err(); err != nil {
+		return nil, fmt.Errorf("error scanning line: %v", err)
+	}
+	if len(paragraph) > 0 {
+		lines = ls.appendParagraph(lines, paragraph)
+	}
+	return lines, nil
+}
+
+// appendParagraph breaks the words of a paragraph into lines,
+// which are appended to the given lines, and records the
+// direction of the paragraph for each of them.
+func (ls *LineScanner) appendParagraph(lines, words []string) []string {
+	paragraphLines := ls.breakParagraph(words)
+	rtl := ls.paragraphRTL(ls.paragraph)
+	for range paragraphLines {
+		ls.linesRTL = append(ls.linesRTL, rtl)
+	}
+	ls.paragraph++
+	return append(lines, paragraphLines...)
+}
+
+// breakParagraph breaks the words of a paragraph into the lines
+// with the least raggedness. An empty paragraph, which comes from
+// consecutive new lines, is output as a single blank line.
+func (ls *LineScanner) breakParagraph(words []string) []string {
+	n := len(words)
+	if n == 0 {
+		return []string{""}
+	}
+	lengths := make([]int, n)
+	for i, word := range words {
+		lengths[i] = displayWidth(word)
+	}
+
+	// cost[i] is the least cost of the lines holding
+	// words[i:], and breaks[i] the index of the word
+	// starting the line after the one starting at i.
+	cost := make([]int, n+1)
+	breaks := make([]int, n+1)
+	for i := n - 1; i >= 0; i-- {
+		cost[i] = -1
+		width := -1
+		for j := i; j < n; j++ {
+			width += lengths[j] + 1
+			if width > ls.lineWidth && j > i {
+				break
+			}
+			lineCost := 0
+			if j < n-1 {
+				slack := ls.lineWidth - width
+				lineCost = slack * slack
+			}
+			if c := lineCost + cost[j+1]; cost[i] < 0 || c < cost[i] {
+				cost[i] = c
+				breaks[i] = j + 1
+			}
+		}
+	}
+
+	var lines []string
+	for i := 0; i < n; i = breaks[i] {
+		line := words[i]
+		for _, word := range words[i+1 : breaks[i]] {
+			line += " " + word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// splitLongWord splits a word which exceeds the line
+// width into pieces which fit on a line, at its break
+// opportunities where possible.
+func (ls *LineScanner) splitLongWord(word string) []string {
+	var pieces []string
+	for displayWidth(word) > ls.lineWidth {
+		head, tail, ok := fitWord(word, ls.lineWidth)
+		if !ok {
+			head, tail = cutWidth(stripSoftHyphens(word), ls.lineWidth)
+		}
+		pieces = append(pieces, head)
+		word = tail
+	}
+	return append(pieces, stripSoftHyphens(word))
+}
@@ -0,0 +1,73 @@
+package texttable
+
+import (
+	"io"
+	"testing"
+)
+
+func TestLineScanner_Next_LineBreaking(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		lineBreaking LineBreaking
+		want         string
+	}{
+		{
+			name:         "greedy",
+			input:        "aaa bb cc ddddd",
+			lineBreaking: BreakGreedy,
+			want: `aaa bb
+cc    
+ddddd 
+`,
+		},
+		{
+			name:         "optimal",
+			input:        "aaa bb cc ddddd",
+			lineBreaking: BreakOptimal,
+			want: `aaa   
+bb cc 
+ddddd 
+`,
+		},
+		{
+			name:         "optimal paragraphs",
+			input:        "aaa bb cc ddddd\n\nee ff\ngg",
+			lineBreaking: BreakOptimal,
+			want: `aaa   
+bb cc 
+ddddd 
+      
+ee ff 
+gg    
+`,
+		},
+		{
+			name:         "optimal long word",
+			input:        "a abcdefghijklm b",
+			lineBreaking: BreakOptimal,
+			want: `a     
+abcdef
+ghijkl
+m b   
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formattedText := ""
+			s := NewLineScanner(tt.input, LineScannerConfig{LineWidth: 6, LineBreaking: tt.lineBreaking})
+			for {
+				line, err := s.Next()
+				if err == io.EOF {
+					break
+				}
+				formattedText += line + "\n"
+			}
+			if formattedText != tt.want {
+				t.Fatalf("expected: \n%v\n\ngot: \n%v\n", tt.want, formattedText)
+			}
+		})
+	}
+}
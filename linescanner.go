@@ -4,27 +4,50 @@ import (
 	"bufio"
 	"fmt"
 	"io"
-	"strconv"
 	"strings"
 	"unicode/utf8"
 )
 
 // LineScanner knows how to read a string, line by line
-// for a given line width. The lines scanned can only be
-// separated by white space, therefore if a word causes the
-// line to go over the line width, it will be added to the
-// next line. Padding with white space is also taken care
-// of, to make sure each line is of equal length.
+// for a given line width. Lines are broken at white space,
+// and at the break opportunities inside words given by the
+// Unicode line breaking algorithm (UAX #14), such as after
+// hyphens and slashes, zero width spaces and soft hyphens,
+// and between ideographs. If a word causes the line to go
+// over the line width, as much of it as can be broken off
+// is kept on the line, and the rest is added to the next
+// line. Widths are measured in terminal columns, in which
+// East Asian wide characters take up two. Padding with white
+// space is also taken care of, to make sure each line is of
+// equal length.
+//
+// When white space is preserved, the lines of the text are
+// kept as they are, with tabs expanded, and are only broken
+// when they exceed the line width.
 //
 // LineScanner should only be constructed with the NewLineScanner
 // function.
 type LineScanner struct {
 	s              *bufio.Scanner
 	lineWidth      int
-	lineFormat     string
+	margin         string
+	alignment      Alignment
+	style          Style
+	color          bool
+	decimal        decimalField
 	overFlow       string
 	newLineReturn  string
 	ignoreNewLines bool
+	preserveSpace  bool
+	tabWidth       int
+	lineBreaking   LineBreaking
+	hyphenator     *Hyphenator
+	lines          []string
+	linesRTL       []bool
+	direction      Direction
+	directions     []bool
+	paragraph      int
+	rtl            bool
 }
 
 // LineScannerConfig should be used to set optional
@@ -33,6 +56,69 @@ type LineScannerConfig struct {
 	LineWidth      int
 	LineMargin     int
 	IgnoreNewLines bool
+	Alignment      Alignment
+	Style          Style
+	// Color sets when the Style is output as ANSI escape
+	// codes. ColorAuto, the zero value, leaves it to
+	// ColorOutput.
+	Color ColorMode
+
+	// PreserveWhiteSpace keeps the indentation and spacing
+	// of each line of the text, instead of collapsing it.
+	// Tabs are expanded to stops every TabWidth columns,
+	// which defaults to 8.
+	PreserveWhiteSpace bool
+	TabWidth           int
+
+	// LineBreaking sets how the lines are broken,
+	// when white space is not preserved.
+	LineBreaking LineBreaking
+
+	// Hyphenator, when set, hyphenates the words which do not
+	// fit on a line, instead of moving them to the next line
+	// or chopping them. Words which hold soft hyphens are only
+	// hyphenated at them.
+	Hyphenator *Hyphenator
+
+	// Direction sets the base direction of each paragraph of
+	// the text, which is detected by default. Every line is
+	// output in visual order, following the Unicode
+	// Bidirectional Algorithm.
+	Direction Direction
+}
+
+// defaultTabWidth is the distance between tab stops
+// used when a tab width is not configured.
+const defaultTabWidth = 8
+
+// Alignment sets how lines which are shorter than
+// the line width are padded with white space.
+type Alignment int
+
+const (
+	// AlignLeft pads lines on the right.
+	AlignLeft Alignment = iota
+	// AlignRight pads lines on the left.
+	AlignRight
+	// AlignCenter pads lines evenly on both sides.
+	AlignCenter
+	// AlignDecimal lines up the decimal separators of the
+	// values in a TextTable column. Lines without a decimal
+	// separator, and lines output by a LineScanner on its own,
+	// are aligned right.
+	AlignDecimal
+	// AlignStart pads lines at the end of their paragraph,
+	// which is on the right of left-to-right paragraphs and
+	// on the left of right-to-left paragraphs.
+	AlignStart
+)
+
+// decimalField describes the widths either side of the
+// decimal separator used to line up the values of a column.
+type decimalField struct {
+	separator     string
+	integerWidth  int
+	fractionWidth int
 }
 
 // NewLineScanner knows how to create a new LineScanner
@@ -49,6 +135,9 @@ func NewLineScanner(text string, config LineScannerConfig) *LineScanner {
 	if config.LineMargin < 0 {
 		config.LineMargin = 0
 	}
+	if config.TabWidth < 1 {
+		config.TabWidth = defaultTabWidth
+	}
 
 	// set configure scanner with
 	// split func
@@ -56,27 +145,42 @@ func NewLineScanner(text string, config LineScannerConfig) *LineScanner {
 	newLineReturn := []byte(strings.Repeat(" ", config.LineWidth))
 
 	var splitFunc bufio.SplitFunc
-	switch config.IgnoreNewLines {
-	case true:
+	switch {
+	case config.PreserveWhiteSpace:
+		splitFunc = bufio.ScanLines
+	case config.IgnoreNewLines:
 		splitFunc = bufio.ScanWords
-	case false:
+	default:
 		splitFunc = scanWordsAndNewLines(newLineReturn)
 	}
 
 	s.Split(splitFunc)
 
-	margin := strings.Repeat(" ", config.LineMargin)
-	lineFormat := margin + "%-" + strconv.Itoa(config.LineWidth) + "v" + margin
-
 	return &LineScanner{
 		s:              s,
 		lineWidth:      config.LineWidth,
-		lineFormat:     lineFormat,
+		margin:         strings.Repeat(" ", config.LineMargin),
+		alignment:      config.Alignment,
+		style:          config.Style,
+		color:          config.Style.code() != "" && colorEnabled(config.Color, nil),
 		overFlow:       "",
 		newLineReturn:  string(newLineReturn),
+		ignoreNewLines: config.IgnoreNewLines,
+		preserveSpace:  config.PreserveWhiteSpace,
+		tabWidth:       config.TabWidth,
+		lineBreaking:   config.LineBreaking,
+		hyphenator:     config.Hyphenator,
+		direction:      config.Direction,
+		directions:     paragraphDirections(text, config.IgnoreNewLines),
 	}
 }
 
+// blank returns a line of white space
+// as wide as the lines of the scanner.
+func (ls *LineScanner) blank() string {
+	return strings.Repeat(" ", ls.lineWidth+2*len(ls.margin))
+}
+
 // Next returns the next line in the text.
 // An io.EOF error is returned to signify that all
 // the text has been read and returned.
@@ -84,58 +188,225 @@ func NewLineScanner(text string, config LineScannerConfig) *LineScanner {
 // configured line width, the word will be split
 // across multiple Next calls.
 func (ls *LineScanner) Next() (string, error) {
-	line := ls.overFlow
+	line, err := ls.next()
+	if err != nil {
+		return "", err
+	}
+	return ls.format(line), nil
+}
 
-	// split overflown words that exceed
-	// line width over multiple lines
-	if len(ls.overFlow) > ls.lineWidth {
-		newLine := ls.overFlow[:ls.lineWidth]
-		ls.overFlow = ls.overFlow[ls.lineWidth:]
-		return fmt.Sprintf(ls.lineFormat, newLine), nil
+// next returns the next line in the text,
+// without any padding or margins.
+func (ls *LineScanner) next() (string, error) {
+	if ls.preserveSpace {
+		return ls.nextPreserved()
+	}
+	if ls.lineBreaking == BreakOptimal {
+		return ls.nextOptimal()
 	}
-	ls.overFlow = ""
 
-	for ls.s.Scan() {
-		if err := ls.s.Err(); err != nil {
-			return "", fmt.Errorf("error scanning line: %v", err)
+	ls.rtl = ls.paragraphRTL(ls.paragraph)
+	line := ""
+	if ls.overFlow != "" {
+		word := ls.overFlow
+		ls.overFlow = ""
+		// break overflown words that exceed
+		// line width over multiple lines
+		if displayWidth(word) > ls.lineWidth {
+			return ls.breakWord("", word), nil
 		}
+		line = stripSoftHyphens(word)
+	}
 
+	for ls.s.Scan() {
 		word := ls.s.Text()
-		// do not append straight to global line
-		// variable, as we need to be able to backtrack
-		// if line width is exceeded
-		var newLine string
-		if line == "" {
-			newLine = word
-		} else {
-			newLine = line + " " + word
-		}
-		// split words that exceed line width
-		// across multiple lines
-		if len(word) > ls.lineWidth {
-			ls.overFlow = newLine[ls.lineWidth:]
-			return fmt.Sprintf(ls.lineFormat, newLine[:ls.lineWidth]), nil
-		}
 		// handle new lines ('\n')
 		if word == ls.newLineReturn {
-			if len(newLine) > 0 {
-				return fmt.Sprintf(ls.lineFormat, line), nil
-			}
-			return fmt.Sprintf(ls.lineFormat, word), nil
+			ls.paragraph++
+			return line, nil
 		}
 
-		if len(newLine) > ls.lineWidth {
-			ls.overFlow = word
-			return fmt.Sprintf(ls.lineFormat, line), nil
+		word = ls.markHyphens(word)
+		// do not append straight to line, as we
+		// need to be able to backtrack if line
+		// width is exceeded
+		newLine := stripSoftHyphens(word)
+		if line != "" {
+			newLine = line + " " + newLine
+		}
+		if displayWidth(newLine) > ls.lineWidth {
+			return ls.breakWord(line, word), nil
 		}
 		// if line width was not exceeded,
-		// set the new line as the global line.
+		// set the new line as the line.
 		line = newLine
 	}
+	if err := ls.s.Err(); err != nil {
+		return "", fmt.Errorf("error scanning line: %v", err)
+	}
 	if line == "" {
 		return "", io.EOF
 	}
-	return fmt.Sprintf(ls.lineFormat, line), nil
+	return line, nil
+}
+
+// breakWord returns the line with as much of a word as fits on
+// it, when broken at one of its break opportunities, and leaves
+// the rest of the word to overflow onto the next line. A word
+// which cannot be broken to fit is moved to the next line, or is
+// chopped at the line width if it would not fit there either.
+func (ls *LineScanner) breakWord(line, word string) string {
+	space := ls.lineWidth
+	if line != "" {
+		space -= displayWidth(line) + 1
+	}
+	if head, tail, ok := fitWord(word, space); ok {
+		ls.overFlow = tail
+		if line == "" {
+			return head
+		}
+		return line + " " + head
+	}
+
+	plain := stripSoftHyphens(word)
+	if line != "" && (displayWidth(plain) <= ls.lineWidth || len(lineBreaks(word)) > 0) {
+		ls.overFlow = word
+		return line
+	}
+	if line != "" {
+		plain = line + " " + plain
+	}
+	line, ls.overFlow = cutWidth(plain, ls.lineWidth)
+	return line
+}
+
+// fitWord breaks a word at the last of its break opportunities
+// which leaves a head that fits in the given space. Heads broken
+// at a soft hyphen are ended with a hyphen. ok is false if the
+// word cannot be broken to fit.
+func fitWord(word string, space int) (head, tail string, ok bool) {
+	breaks := lineBreaks(word)
+	for i := len(breaks) - 1; i >= 0; i-- {
+		head = word[:breaks[i]]
+		if strings.HasSuffix(head, softHyphen) {
+			head = stripSoftHyphens(head) + "-"
+		} else {
+			head = stripSoftHyphens(head)
+		}
+		if displayWidth(head) <= space {
+			return head, word[breaks[i]:], true
+		}
+	}
+	return "", "", false
+}
+
+// nextPreserved returns the next line in the text when white
+// space is preserved. Lines which exceed the line width are
+// broken at the last space which fits, or at the line width
+// if there is no such space.
+func (ls *LineScanner) nextPreserved() (string, error) {
+	if ls.overFlow == "" {
+		if !ls.s.Scan() {
+			if err := ls.s.Err(); err != nil {
+				return "", fmt.Errorf("error scanning line: %v", err)
+			}
+			return "", io.EOF
+		}
+		line := strings.TrimRightFunc(expandTabs(ls.s.Text(), ls.tabWidth), isSpace)
+		ls.rtl = ls.paragraphRTL(ls.paragraph)
+		ls.paragraph++
+		if line == "" {
+			return "", nil
+		}
+		ls.overFlow = line
+	}
+
+	line := ls.overFlow
+	if displayWidth(line) <= ls.lineWidth {
+		ls.overFlow = ""
+		return line, nil
+	}
+	head, _ := cutWidth(line, ls.lineWidth)
+	cut := len(head)
+	for i := len(head); i > 0; i-- {
+		if line[i] == ' ' && line[i-1] != ' ' {
+			cut = i
+			break
+		}
+	}
+	ls.overFlow = strings.TrimLeft(line[cut:], " ")
+	return line[:cut], nil
+}
+
+// expandTabs replaces the tabs of a line with the spaces
+// needed to reach the next tab stop.
+func expandTabs(line string, tabWidth int) string {
+	if !strings.ContainsRune(line, '\t') {
+		return line
+	}
+	var b strings.Builder
+	column := 0
+	for _, r := range line {
+		if r == '\t' {
+			spaces := tabWidth - column%tabWidth
+			b.WriteString(strings.Repeat(" ", spaces))
+			column += spaces
+			continue
+		}
+		b.WriteRune(r)
+		column += runeWidth(r)
+	}
+	return b.String()
+}
+
+// format puts the line in visual order, pads it to the
+// line width, according to the alignment, applies the style
+// and surrounds it with the line margin.
+func (ls *LineScanner) format(line string) string {
+	line = reorderLine(line, ls.rtl)
+	alignment := ls.alignment
+	if alignment == AlignStart && ls.rtl {
+		alignment = AlignRight
+	}
+	if alignment == AlignDecimal && ls.decimal.separator != "" {
+		line = ls.decimal.align(line)
+	}
+	return ls.margin + ls.style.apply(pad(line, ls.lineWidth, alignment), ls.color) + ls.margin
+}
+
+// align pads a line so that its decimal separator lines up
+// with the separators of the other lines in the field. Lines
+// without a separator are returned as they are.
+func (df decimalField) align(line string) string {
+	i := strings.Index(line, df.separator)
+	if i < 0 {
+		return line
+	}
+	integerWidth := displayWidth(line[:i])
+	fractionWidth := displayWidth(line[i:])
+	if integerWidth > df.integerWidth || fractionWidth > df.fractionWidth {
+		return line
+	}
+	return strings.Repeat(" ", df.integerWidth-integerWidth) + line +
+		strings.Repeat(" ", df.fractionWidth-fractionWidth)
+}
+
+// pad pads a line with white space to the given
+// width, according to the alignment.
+func pad(line string, width int, alignment Alignment) string {
+	space := width - displayWidth(line)
+	if space <= 0 {
+		return line
+	}
+	switch alignment {
+	case AlignRight, AlignDecimal:
+		return strings.Repeat(" ", space) + line
+	case AlignCenter:
+		left := space / 2
+		return strings.Repeat(" ", left) + line + strings.Repeat(" ", space-left)
+	default:
+		return line + strings.Repeat(" ", space)
+	}
 }
 
 // scanWordsAndNewLines returns a split function for a Scanner that
@@ -27,7 +27,127 @@ func TestLineScanner_Next(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			formattedText := ""
-			s := NewLineScanner(tt.input, LineScannerConfig{25, 2, false})
+			s := NewLineScanner(tt.input, LineScannerConfig{LineWidth: 25, LineMargin: 2})
+			for {
+				line, err := s.Next()
+				if err == io.EOF {
+					break
+				}
+				formattedText += line + "\n"
+			}
+			if formattedText != tt.want {
+				t.Fatalf("expected: \n%v\n\ngot: \n%v\n", tt.want, formattedText)
+			}
+		})
+	}
+}
+
+func TestLineScanner_Next_Alignment(t *testing.T) {
+	input := "hello there, today is a tuesday"
+
+	tests := []struct {
+		name      string
+		alignment Alignment
+		want      string
+	}{
+		{
+			name:      "left",
+			alignment: AlignLeft,
+			want: ` hello there,  
+ today is a    
+ tuesday       
+`,
+		},
+		{
+			name:      "right",
+			alignment: AlignRight,
+			want: `  hello there, 
+    today is a 
+       tuesday 
+`,
+		},
+		{
+			name:      "center",
+			alignment: AlignCenter,
+			want: ` hello there,  
+  today is a   
+    tuesday    
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formattedText := ""
+			s := NewLineScanner(input, LineScannerConfig{LineWidth: 13, LineMargin: 1, Alignment: tt.alignment})
+			for {
+				line, err := s.Next()
+				if err == io.EOF {
+					break
+				}
+				formattedText += line + "\n"
+			}
+			if formattedText != tt.want {
+				t.Fatalf("expected: \n%v\n\ngot: \n%v\n", tt.want, formattedText)
+			}
+		})
+	}
+}
+
+func TestLineScanner_Next_PreserveWhiteSpace(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		tabWidth int
+		want     string
+	}{
+		{
+			name:  "indented code",
+			input: "func main() {\n\tif ok {\n\t\treturn\n\t}\n}",
+			want: `func main() {       
+        if ok {     
+                retu
+rn                  
+        }           
+}                   
+`,
+		},
+		{
+			name:     "tab width",
+			input:    "a\tb\n\tc  d",
+			tabWidth: 4,
+			want: `a   b               
+    c  d            
+`,
+		},
+		{
+			name:  "long line broken at space",
+			input: "  at example.com/pkg.(*Type).Method(0x1, 0x2)\n\n  at main.main()",
+			want: `  at                
+example.com/pkg.(*Ty
+pe).Method(0x1, 0x2)
+                    
+  at main.main()    
+`,
+		},
+		{
+			name:  "wide characters",
+			input: "漢字漢字漢字漢字漢字漢字\n\t字",
+			want: `漢字漢字漢字漢字漢字
+漢字                
+        字          
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formattedText := ""
+			s := NewLineScanner(tt.input, LineScannerConfig{
+				LineWidth:          20,
+				PreserveWhiteSpace: true,
+				TabWidth:           tt.tabWidth,
+			})
 			for {
 				line, err := s.Next()
 				if err == io.EOF {
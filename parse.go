@@ -0,0 +1,274 @@
+package texttable
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var errNoColumns = errors.New("could not detect any columns")
+
+// ParseConfig should be used to describe the layout of
+// the text table being parsed. A ColumnWidth of 0 means
+// that the columns are detected from the alignment of
+// the white space in the text.
+type ParseConfig struct {
+	ColumnWidth  int
+	ColumnMargin int
+}
+
+// minColumnGap is the smallest run of white space which
+// separates two columns when the columns are detected.
+const minColumnGap = 2
+
+var (
+	// ruleLineRegex matches the horizontal rules of boxed
+	// tables, such as those output by MySQL and psql, and
+	// the delimiter rows of Markdown tables.
+	ruleLineRegex = regexp.MustCompile(`^[\s|+:=-]*-{3,}[\s|+:=-]*$`)
+	// rowCountRegex matches the row count printed by psql.
+	rowCountRegex = regexp.MustCompile(`^\(\d+ rows?\)$`)
+)
+
+// Parse reverses TextTable.Output, returning the cells of a
+// text table as a 2D string slice. The wrapped lines of each
+// cell are joined with spaces, or without one where the line
+// was broken inside a word, and blank lines within a cell
+// become new lines. Columns are measured in display width.
+// Boxed tables, such as MySQL and psql output and Markdown
+// tables, are recognised by their rules and parsed by their
+// column delimiters instead.
+func Parse(text string, config ParseConfig) ([][]string, error) {
+	lines := strings.Split(strings.Replace(text, "\r\n", "\n", -1), "\n")
+	for _, line := range lines {
+		if ruleLineRegex.MatchString(line) && strings.ContainsAny(line, "|+") {
+			return parseBoxed(lines), nil
+		}
+	}
+
+	var columns [][2]int
+	if config.ColumnWidth > 0 {
+		columns = fixedColumns(lines, config.ColumnWidth, config.ColumnMargin)
+	} else {
+		columns = detectColumns(lines)
+	}
+	if len(columns) == 0 {
+		if strings.TrimSpace(text) == "" {
+			return [][]string{}, nil
+		}
+		return nil, errNoColumns
+	}
+
+	var textTable [][]string
+	for _, rowLines := range splitRows(lines) {
+		row := make([]string, len(columns))
+		for x, column := range columns {
+			var segments []string
+			for _, line := range rowLines {
+				segments = append(segments, columnSlice(line, column[0], column[1]))
+			}
+			row[x] = joinSegments(segments)
+		}
+		textTable = append(textTable, row)
+	}
+	return textTable, nil
+}
+
+// parseBoxed parses the lines of a table whose cells
+// are delimited by pipes, skipping any rule lines.
+func parseBoxed(lines []string) [][]string {
+	var textTable [][]string
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || ruleLineRegex.MatchString(line) || rowCountRegex.MatchString(line) {
+			continue
+		}
+		// protect pipes escaped in Markdown
+		line = strings.Replace(line, `\|`, "\x00", -1)
+		line = strings.TrimPrefix(line, "|")
+		line = strings.TrimSuffix(line, "|")
+		cells := strings.Split(line, "|")
+		for i := range cells {
+			cells[i] = strings.Replace(strings.TrimSpace(cells[i]), "\x00", "|", -1)
+		}
+		textTable = append(textTable, cells)
+	}
+	return textTable
+}
+
+// fixedColumns returns the display column ranges of the
+// columns of a text table output with the given column
+// width and margin.
+func fixedColumns(lines []string, width, margin int) [][2]int {
+	if margin < 0 {
+		margin = 0
+	}
+	columnWidth := width + 2*margin
+	maxLength := 0
+	for _, line := range lines {
+		if n := displayWidth(line); n > maxLength {
+			maxLength = n
+		}
+	}
+	var columns [][2]int
+	for start := 0; start < maxLength; start += columnWidth {
+		columns = append(columns, [2]int{start + margin, start + margin + width})
+	}
+	return columns
+}
+
+// detectColumns returns the display column ranges of the
+// columns of a text table, found by looking for runs of white
+// space which are aligned across every line.
+func detectColumns(lines []string) [][2]int {
+	var used []bool
+	for _, line := range lines {
+		i := 0
+		for _, r := range line {
+			for w := runeWidth(r); w > 0; w-- {
+				if i >= len(used) {
+					used = append(used, false)
+				}
+				if r != ' ' {
+					used[i] = true
+				}
+				i++
+			}
+		}
+	}
+
+	var columns [][2]int
+	start, gap := -1, 0
+	for i, u := range used {
+		switch {
+		case u && start < 0:
+			start = i
+		case u:
+		case start >= 0:
+			gap++
+			if gap >= minColumnGap && (i+1 == len(used) || used[i+1]) {
+				columns = append(columns, [2]int{start, i + 1 - gap})
+				start = -1
+			}
+			continue
+		}
+		gap = 0
+	}
+	if start >= 0 {
+		columns = append(columns, [2]int{start, len(used) - gap})
+	}
+	return columns
+}
+
+// splitRows groups the lines of a text table into rows.
+// Rows are separated by empty lines if the text has any,
+// which is the case for row margins greater than one,
+// otherwise by lines of white space.
+func splitRows(lines []string) [][]string {
+	// the last line of the output is the
+	// filler line of the last row
+	hasEmpty := false
+	for _, line := range lines[:len(lines)-1] {
+		if line == "" {
+			hasEmpty = true
+			break
+		}
+	}
+
+	var rows [][]string
+	var row []string
+	for _, line := range lines {
+		isBreak := line == ""
+		if !hasEmpty {
+			isBreak = strings.TrimSpace(line) == ""
+		}
+		if isBreak {
+			if len(row) > 0 {
+				rows = append(rows, row)
+			}
+			row = nil
+			continue
+		}
+		row = append(row, line)
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// joinSegments joins the wrapped lines of a cell into its
+// text, treating blank lines as paragraph breaks.
+func joinSegments(segments []string) string {
+	var paragraphs []string
+	paragraph := ""
+	for _, segment := range segments {
+		segment = strings.TrimSpace(segment)
+		if segment == "" {
+			if paragraph != "" {
+				paragraphs = append(paragraphs, paragraph)
+			}
+			paragraph = ""
+			continue
+		}
+		switch {
+		case paragraph == "":
+			paragraph = segment
+		case brokenInWord(paragraph, segment):
+			paragraph += segment
+		default:
+			paragraph += " " + segment
+		}
+	}
+	if paragraph != "" {
+		paragraphs = append(paragraphs, paragraph)
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// brokenInWord reports whether a line ending with before was
+// broken inside a word, at one of its break opportunities, from
+// a line starting with after. Only breaks after a hyphen and
+// between wide characters, which are written without spaces,
+// are taken to be inside a word.
+func brokenInWord(before, after string) bool {
+	last, _ := utf8.DecodeLastRuneInString(before)
+	first, _ := utf8.DecodeRuneInString(after)
+	if last != '-' && last != '\u2010' && !(isWide(last) && isWide(first)) {
+		return false
+	}
+	// the break is checked in the word made of the
+	// last word of before and the first of after
+	head := before[strings.LastIndexFunc(before, isSpace)+1:]
+	tail := after
+	if i := strings.IndexFunc(after, isSpace); i >= 0 {
+		tail = after[:i]
+	}
+	for _, i := range lineBreaks(head + tail) {
+		if i == len(head) {
+			return true
+		}
+	}
+	return false
+}
+
+// columnSlice returns the runes of s which start from the
+// display column start up to end, allowing for the range to
+// go past the end of s. Runes which take up no columns go with
+// the rune before them.
+func columnSlice(s string, start, end int) string {
+	var b strings.Builder
+	column, last := 0, 0
+	for _, r := range s {
+		w := runeWidth(r)
+		if w > 0 {
+			last = column
+		}
+		if last >= start && last < end {
+			b.WriteRune(r)
+		}
+		column += w
+	}
+	return b.String()
+}
@@ -0,0 +1,166 @@
+package texttable
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		config ParseConfig
+		want   [][]string
+	}{
+		{
+			name: "text table with known layout",
+			input: `  name            description   
+                                
+  apple           a round       
+                  fruit that    
+                  grows on      
+                  trees         
+                                `,
+			config: ParseConfig{ColumnWidth: 12, ColumnMargin: 2},
+			want: [][]string{
+				{"name", "description"},
+				{"apple", "a round fruit that grows on trees"},
+			},
+		},
+		{
+			name: "text table with detected layout",
+			input: `  name            description   
+                                
+  apple           a round       
+                  fruit that    
+                  grows on      
+                  trees         
+                                `,
+			want: [][]string{
+				{"name", "description"},
+				{"apple", "a round fruit that grows on trees"},
+			},
+		},
+		{
+			name: "text table with blank lines in cells",
+			input: "  pear            green         \n" +
+				"                                \n" +
+				"                  and sweet     \n" +
+				"                                \n" +
+				"\n" +
+				"  plum            purple        \n" +
+				"                                ",
+			want: [][]string{
+				{"pear", "green\n\nand sweet"},
+				{"plum", "purple"},
+			},
+		},
+		{
+			name: "mysql",
+			input: `+----+---------+
+| id | name    |
++----+---------+
+|  1 | apple   |
+|  2 | pear    |
++----+---------+
+`,
+			want: [][]string{
+				{"id", "name"},
+				{"1", "apple"},
+				{"2", "pear"},
+			},
+		},
+		{
+			name: "psql",
+			input: ` id | name
+----+-------
+  1 | apple
+  2 | pear
+(2 rows)
+`,
+			want: [][]string{
+				{"id", "name"},
+				{"1", "apple"},
+				{"2", "pear"},
+			},
+		},
+		{
+			name: "markdown",
+			input: `| id | name |
+|:---|-----:|
+| 1 | apple \| pear |
+`,
+			want: [][]string{
+				{"id", "name"},
+				{"1", "apple | pear"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input, tt.config)
+			if err != nil {
+				t.Fatalf("failed to parse text table: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected: %q, got: %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParse_RoundTrip(t *testing.T) {
+	input := [][]string{
+		{"Lorem1 ipsum dolor sit amet, consectetur adipiscing elit", "sed do eiusmod"},
+		{"tempor incididunt", "ut labore et dolore magna aliqua. Pharetra pharetra massa massa ultricies"},
+	}
+	want := [][]string{
+		{input[0][0], input[0][1]},
+		{input[1][0], input[1][1]},
+	}
+
+	output, err := New(input, Config{ColumnWidth: 20, ColumnMargin: 2, RowMargin: 1}).Output()
+	if err != nil {
+		t.Fatalf("failed to format text table: %v", err)
+	}
+
+	got, err := Parse(output, ParseConfig{ColumnWidth: 20, ColumnMargin: 2})
+	if err != nil {
+		t.Fatalf("failed to parse text table: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected: %q, got: %q", want, got)
+	}
+}
+
+func TestParse_RoundTripWide(t *testing.T) {
+	input := [][]string{
+		{"名前", "説明"},
+		{"赤いりんご", "果物です。とても甘いです"},
+		{"drop-outs", "a well-known - and well-loved - phrase"},
+	}
+	tests := []struct {
+		name   string
+		config ParseConfig
+	}{
+		{"known layout", ParseConfig{ColumnWidth: 8, ColumnMargin: 2}},
+		{"detected layout", ParseConfig{}},
+	}
+
+	output, err := New(input, Config{ColumnWidth: 8, ColumnMargin: 2, RowMargin: 1}).Output()
+	if err != nil {
+		t.Fatalf("failed to format text table: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(output, tt.config)
+			if err != nil {
+				t.Fatalf("failed to parse text table: %v", err)
+			}
+			if !reflect.DeepEqual(got, input) {
+				t.Fatalf("expected: \n%q\n\ngot: \n%q\n", input, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package texttable
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// recordHeading is the format of the line output
+// above every record of an expanded table.
+const recordHeading = "-[ RECORD %v ]"
+
+// Transpose returns a new 2D string slice with the rows and
+// columns of the text table swapped. Rows which are shorter
+// than the longest row are padded with empty strings.
+func Transpose(textTable [][]string) [][]string {
+	var width int
+	for _, row := range textTable {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	transposed := makeMatrix(len(textTable), width)
+	for y, row := range textTable {
+		for x, text := range row {
+			transposed[x][y] = text
+		}
+	}
+	return transposed
+}
+
+// outputRecords produces the expanded text table, where every
+// row after the header rows is output as a record of heading
+// and value pairs. Each value keeps the alignment of its column,
+// though values aligned with AlignDecimal are aligned right, as
+// a record holds a single value of each column.
+func (tf *TextTable) outputRecords() (string, error) {
+	var width int
+	if len(tf.textTable) > 0 {
+		width = len(tf.textTable[0])
+	}
+	headings := make([]string, width)
+	for x := range headings {
+		if tf.config.HeaderRows > 0 && len(tf.textTable) > 0 {
+			headings[x] = tf.textTable[0][x]
+		} else {
+			headings[x] = strconv.Itoa(x + 1)
+		}
+	}
+
+	body := tf.textTable
+	if tf.config.HeaderRows < len(body) {
+		body = body[tf.config.HeaderRows:]
+	} else {
+		body = nil
+	}
+
+	records := make([]string, len(body))
+	for n, row := range body {
+		y := n + tf.config.HeaderRows
+		pairs := makeMatrix(2, width)
+		config := tf.recordConfig()
+		config.CellStyles = make(map[Cell]Style)
+		config.cellAlignments = make(map[Cell]Alignment)
+		for x, value := range row {
+			pairs[x][0] = headings[x]
+			pairs[x][1] = tf.cellText(value, y, x)
+			config.CellStyles[Cell{Row: x, Column: 1}] = tf.cellStyle(value, y, x, false)
+			config.cellAlignments[Cell{Row: x, Column: 1}] = tf.columnAlignment(x)
+		}
+
+		recordTable := New(pairs, config)
+		recordTable.color = tf.color
+		record, err := recordTable.outputBands([][]int{{0, 1}})
+		if err != nil {
+			return "", err
+		}
+
+		heading := fmt.Sprintf(recordHeading, n+1)
+		if padding := recordTable.bandWidth([]int{0, 1}) - len(heading); padding > 0 {
+			heading += strings.Repeat("-", padding)
+		}
+		records[n] = heading + "\n" + record
+	}
+	return strings.Join(records, "\n"), nil
+}
+
+// recordConfig returns the Config of the tables used to output
+// records, whose heading column is as wide as the columns of the
+// table, and whose value column is as wide as its widest column.
+func (tf *TextTable) recordConfig() Config {
+	valueWidth := tf.config.ColumnWidth
+	for _, width := range tf.config.ColumnWidths {
+		if width > valueWidth {
+			valueWidth = width
+		}
+	}
+	return Config{
+		ColumnWidth:        tf.config.ColumnWidth,
+		ColumnWidths:       map[int]int{1: valueWidth},
+		ColumnMargin:       tf.config.ColumnMargin,
+		RowMargin:          tf.config.RowMargin,
+		IgnoreNewLines:     tf.config.IgnoreNewLines,
+		PreserveWhiteSpace: tf.config.PreserveWhiteSpace,
+		TabWidth:           tf.config.TabWidth,
+		LineBreaking:       tf.config.LineBreaking,
+		Hyphenator:         tf.config.Hyphenator,
+		DecimalSeparator:   tf.config.DecimalSeparator,
+		RightToLeft:        tf.config.RightToLeft,
+	}
+}
+
+// makeMatrix creates a 2D string slice
+// using a given width and height.
+func makeMatrix(width, height int) [][]string {
+	matrix := make([][]string, height)
+	for i := range matrix {
+		matrix[i] = make([]string, width)
+	}
+	return matrix
+}
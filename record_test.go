@@ -0,0 +1,137 @@
+package texttable
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTranspose(t *testing.T) {
+	tests := []struct {
+		name  string
+		input [][]string
+		want  [][]string
+	}{
+		{
+			name:  "square",
+			input: [][]string{{"a", "b"}, {"c", "d"}},
+			want:  [][]string{{"a", "c"}, {"b", "d"}},
+		},
+		{
+			name:  "non constant row length",
+			input: [][]string{{"a", "b", "c"}, {"d"}},
+			want:  [][]string{{"a", "d"}, {"b", ""}, {"c", ""}},
+		},
+		{
+			name:  "empty",
+			input: [][]string{},
+			want:  [][]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Transpose(tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected: %q, got: %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTextTableFormatter_Output_Expanded(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      [][]string
+		headerRows int
+		want       string
+	}{
+		{
+			name: "with header",
+			input: [][]string{
+				{"id", "name", "notes"},
+				{"1", "apple", "a round fruit that grows on trees"},
+				{"2", "pear", ""},
+			},
+			headerRows: 1,
+			want: `-[ RECORD 1 ]-----------
+ id          1          
+                        
+ name        apple      
+                        
+ notes       a round    
+             fruit that 
+             grows on   
+             trees      
+                        
+-[ RECORD 2 ]-----------
+ id          2          
+                        
+ name        pear       
+                        
+ notes                  
+                        `,
+		},
+		{
+			name: "without header",
+			input: [][]string{
+				{"1", "apple"},
+			},
+			want: `-[ RECORD 1 ]-----------
+ 1           1          
+                        
+ 2           apple      
+                        `,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ttf := New(tt.input, Config{
+				ColumnWidth:  10,
+				ColumnMargin: 1,
+				RowMargin:    1,
+				HeaderRows:   tt.headerRows,
+				Expanded:     true,
+			})
+
+			output, err := ttf.Output()
+			if err != nil {
+				t.Fatalf("failed to format text table: %v", err)
+			}
+
+			if output != tt.want {
+				t.Fatalf("expected: \n%v\n\ngot: \n%v\n", tt.want, output)
+			}
+		})
+	}
+}
+
+func TestTextTableFormatter_Output_Expanded_ColumnOptions(t *testing.T) {
+	input := [][]string{
+		{"item", "price"},
+		{"apple", "1.5"},
+	}
+	want := `-[ RECORD 1 ]------------
+ item     apple          
+                         
+ price               1.5 
+                         `
+
+	ttf := New(input, Config{
+		ColumnWidth:  7,
+		ColumnMargin: 1,
+		RowMargin:    1,
+		HeaderRows:   1,
+		Expanded:     true,
+		ColumnWidths: map[int]int{1: 14},
+		Alignments:   map[int]Alignment{1: AlignDecimal},
+	})
+
+	output, err := ttf.Output()
+	if err != nil {
+		t.Fatalf("failed to format text table: %v", err)
+	}
+
+	if output != want {
+		t.Fatalf("expected: \n%v\n\ngot: \n%v\n", want, output)
+	}
+}
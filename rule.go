@@ -0,0 +1,112 @@
+package texttable
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Rule is a conditional formatting rule, evaluated against
+// the raw values of cells when the table is output. Rules
+// should be created with the NewRule and NewThresholdRule
+// functions.
+type Rule struct {
+	// Columns holds the indexes of the columns the
+	// rule applies to. The rule applies to all columns
+	// if it is empty.
+	Columns []int
+	// Evaluate returns the style to overlay on a cell
+	// with the given value, and whether the rule matched.
+	Evaluate func(value string) (Style, bool)
+}
+
+// Condition reports whether the value of a cell matches.
+type Condition func(value string) bool
+
+// Threshold pairs a lower bound for the numeric
+// values of a column with the style applied to them.
+type Threshold struct {
+	Value float64
+	Style Style
+}
+
+// NewRule creates a Rule which overlays the style on the cells
+// of the given columns whose values match the condition. The
+// rule applies to all columns if no columns are given.
+func NewRule(condition Condition, style Style, columns ...int) Rule {
+	return Rule{
+		Columns: columns,
+		Evaluate: func(value string) (Style, bool) {
+			return style, condition(value)
+		},
+	}
+}
+
+// NewThresholdRule creates a Rule which overlays a style on the
+// numeric cells of the given columns, according to a color scale.
+// Each cell takes the style of the highest threshold whose value
+// it reaches, and cells below every threshold are left as they are.
+func NewThresholdRule(thresholds []Threshold, columns ...int) Rule {
+	return Rule{
+		Columns: columns,
+		Evaluate: func(value string) (Style, bool) {
+			n, ok := parseNumber(value)
+			if !ok {
+				return Style{}, false
+			}
+			var style Style
+			var matched bool
+			var highest float64
+			for _, threshold := range thresholds {
+				if n >= threshold.Value && (!matched || threshold.Value >= highest) {
+					style, highest, matched = threshold.Style, threshold.Value, true
+				}
+			}
+			return style, matched
+		},
+	}
+}
+
+// appliesTo reports whether the rule applies to the column.
+func (r Rule) appliesTo(x int) bool {
+	if len(r.Columns) == 0 {
+		return true
+	}
+	for _, column := range r.Columns {
+		if column == x {
+			return true
+		}
+	}
+	return false
+}
+
+// LessThan returns a Condition matching numeric values below n.
+func LessThan(n float64) Condition {
+	return func(value string) bool {
+		v, ok := parseNumber(value)
+		return ok && v < n
+	}
+}
+
+// GreaterThan returns a Condition matching numeric values above n.
+func GreaterThan(n float64) Condition {
+	return func(value string) bool {
+		v, ok := parseNumber(value)
+		return ok && v > n
+	}
+}
+
+// Equals returns a Condition matching values equal to s,
+// ignoring any surrounding white space.
+func Equals(s string) Condition {
+	return func(value string) bool {
+		return strings.TrimSpace(value) == s
+	}
+}
+
+// Matches returns a Condition matching values
+// which contain a match of the regular expression.
+func Matches(re *regexp.Regexp) Condition {
+	return func(value string) bool {
+		return re.MatchString(value)
+	}
+}
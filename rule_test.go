@@ -0,0 +1,82 @@
+package texttable
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestRule_Evaluate(t *testing.T) {
+	red := Style{Foreground: Red}
+	scale := []Threshold{
+		{Value: 0, Style: Style{Foreground: Red}},
+		{Value: 50, Style: Style{Foreground: Yellow}},
+		{Value: 80, Style: Style{Foreground: Green}},
+	}
+
+	tests := []struct {
+		name      string
+		rule      Rule
+		input     string
+		wantStyle Style
+		wantMatch bool
+	}{
+		{name: "less than match", rule: NewRule(LessThan(0), red), input: "-1.5", wantStyle: red, wantMatch: true},
+		{name: "less than no match", rule: NewRule(LessThan(0), red), input: "2", wantStyle: red},
+		{name: "less than not a number", rule: NewRule(LessThan(0), red), input: "n/a", wantStyle: red},
+		{name: "greater than", rule: NewRule(GreaterThan(10), red), input: "1,000", wantStyle: red, wantMatch: true},
+		{name: "equals", rule: NewRule(Equals("fail"), red), input: " fail ", wantStyle: red, wantMatch: true},
+		{name: "matches", rule: NewRule(Matches(regexp.MustCompile(`(?i)fail`)), red), input: "FAILED", wantStyle: red, wantMatch: true},
+		{name: "threshold low", rule: NewThresholdRule(scale), input: "10", wantStyle: Style{Foreground: Red}, wantMatch: true},
+		{name: "threshold middle", rule: NewThresholdRule(scale), input: "50", wantStyle: Style{Foreground: Yellow}, wantMatch: true},
+		{name: "threshold high", rule: NewThresholdRule(scale), input: "99", wantStyle: Style{Foreground: Green}, wantMatch: true},
+		{name: "threshold below", rule: NewThresholdRule(scale), input: "-5"},
+		{name: "threshold not a number", rule: NewThresholdRule(scale), input: "score"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			style, matched := tt.rule.Evaluate(tt.input)
+			if matched != tt.wantMatch {
+				t.Fatalf("expected match: %v, got: %v", tt.wantMatch, matched)
+			}
+			if matched && style != tt.wantStyle {
+				t.Fatalf("expected style: %+v, got: %+v", tt.wantStyle, style)
+			}
+		})
+	}
+}
+
+func TestTextTableFormatter_Output_Rules(t *testing.T) {
+	input := [][]string{
+		{"status", "diff"},
+		{"ok", "-3"},
+		{"fail", "4"},
+	}
+	want := " status  diff   \n" +
+		"                \n" +
+		" ok      \x1b[31m-3\x1b[0m     \n" +
+		"                \n" +
+		" \x1b[1;31;41mfail\x1b[0m    4      \n" +
+		"                "
+
+	ttf := New(input, Config{
+		ColumnWidth:  6,
+		ColumnMargin: 1,
+		RowMargin:    1,
+		HeaderRows:   1,
+		Rules: []Rule{
+			NewRule(LessThan(0), Style{Foreground: Red}, 1),
+			NewRule(Matches(regexp.MustCompile(`fail`)), Style{Foreground: Red, Background: Red, Bold: true}, 0),
+		},
+		Color: ColorAlways,
+	})
+
+	output, err := ttf.Output()
+	if err != nil {
+		t.Fatalf("failed to format text table: %v", err)
+	}
+
+	if output != want {
+		t.Fatalf("expected: \n%q\n\ngot: \n%q\n", want, output)
+	}
+}
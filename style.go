@@ -0,0 +1,183 @@
+package texttable
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ColorMode sets when styles are output as ANSI escape codes.
+type ColorMode int
+
+const (
+	// ColorAuto outputs styles when the output is a terminal,
+	// unless the NO_COLOR environment variable is set. Only
+	// a Writer and TextTable.WriteTo know their output, so
+	// the tables returned as strings by Output, and the lines
+	// of a LineScanner, are never styled.
+	ColorAuto ColorMode = iota
+	// ColorNever never outputs styles.
+	ColorNever
+	// ColorAlways always outputs styles.
+	ColorAlways
+)
+
+// ColorOutput sets when styles are output as ANSI escape
+// codes, for the tables and line scanners whose config
+// leaves it to ColorAuto. It defaults to ColorAuto, and is
+// read when they are created.
+var ColorOutput ColorMode
+
+// Color is a terminal color. The zero value
+// leaves the terminal's default color in place.
+type Color int
+
+// The standard and bright terminal colors.
+const (
+	Black Color = iota + 1
+	Red
+	Green
+	Yellow
+	Blue
+	Magenta
+	Cyan
+	White
+	BrightBlack
+	BrightRed
+	BrightGreen
+	BrightYellow
+	BrightBlue
+	BrightMagenta
+	BrightCyan
+	BrightWhite
+)
+
+// Style describes how the text of a cell is output
+// on a terminal. The zero value applies no styling.
+type Style struct {
+	Foreground Color
+	Background Color
+	Bold       bool
+	Italic     bool
+	Underline  bool
+	Dim        bool
+}
+
+// Cell identifies a single cell of a table
+// by its row and column indexes.
+type Cell struct {
+	Row    int
+	Column int
+}
+
+// resetCode resets all terminal styling.
+const resetCode = "\x1b[0m"
+
+// merge returns the style with the set
+// fields of the other style laid over it.
+func (s Style) merge(other Style) Style {
+	if other.Foreground != 0 {
+		s.Foreground = other.Foreground
+	}
+	if other.Background != 0 {
+		s.Background = other.Background
+	}
+	s.Bold = s.Bold || other.Bold
+	s.Italic = s.Italic || other.Italic
+	s.Underline = s.Underline || other.Underline
+	s.Dim = s.Dim || other.Dim
+	return s
+}
+
+// code returns the ANSI escape code which turns the style
+// on, or an empty string if the style applies no styling.
+func (s Style) code() string {
+	var params []string
+	if s.Bold {
+		params = append(params, "1")
+	}
+	if s.Dim {
+		params = append(params, "2")
+	}
+	if s.Italic {
+		params = append(params, "3")
+	}
+	if s.Underline {
+		params = append(params, "4")
+	}
+	if s.Foreground != 0 {
+		params = append(params, strconv.Itoa(colorCode(s.Foreground, 30)))
+	}
+	if s.Background != 0 {
+		params = append(params, strconv.Itoa(colorCode(s.Background, 40)))
+	}
+	if len(params) == 0 {
+		return ""
+	}
+	return "\x1b[" + strings.Join(params, ";") + "m"
+}
+
+// apply surrounds the visible text of a line with the
+// escape codes of the style, leaving any white space
+// padding on either side of it unstyled. The line is left as
+// it is when color is not set.
+func (s Style) apply(line string, color bool) string {
+	code := s.code()
+	if code == "" || !color {
+		return line
+	}
+	text := strings.TrimSpace(line)
+	if text == "" {
+		return line
+	}
+	start := strings.Index(line, text)
+	return line[:start] + code + text + resetCode + line[start+len(text):]
+}
+
+// colorCode returns the SGR parameter of a color,
+// given the base parameter of the standard colors.
+func colorCode(c Color, base int) int {
+	if c >= BrightBlack {
+		return base + 60 + int(c-BrightBlack)
+	}
+	return base + int(c-Black)
+}
+
+// ColorEnabled reports whether styles are output as ANSI
+// escape codes when writing to w, as set by ColorOutput.
+func ColorEnabled(w io.Writer) bool {
+	return colorEnabled(ColorAuto, w)
+}
+
+// colorEnabled reports whether styles are output as ANSI
+// escape codes when writing to w with the given mode, which
+// is taken from ColorOutput if it is ColorAuto. A nil w is an
+// unknown output, which is not taken to be a terminal.
+func colorEnabled(mode ColorMode, w io.Writer) bool {
+	if mode == ColorAuto {
+		mode = ColorOutput
+	}
+	switch mode {
+	case ColorNever:
+		return false
+	case ColorAlways:
+		return true
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	return ok && isTerminal(f)
+}
+
+// isTerminal reports whether the file is a terminal. Character
+// devices other than the null device are taken to be terminals.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	null, err := os.Stat(os.DevNull)
+	return err != nil || !os.SameFile(fi, null)
+}
@@ -0,0 +1,166 @@
+package texttable
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestStyle_apply(t *testing.T) {
+	tests := []struct {
+		name  string
+		style Style
+		input string
+		want  string
+	}{
+		{
+			name:  "no style",
+			input: "hello   ",
+			want:  "hello   ",
+		},
+		{
+			name:  "foreground",
+			style: Style{Foreground: Red},
+			input: "hello   ",
+			want:  "\x1b[31mhello\x1b[0m   ",
+		},
+		{
+			name:  "bright background and bold",
+			style: Style{Background: BrightBlue, Bold: true},
+			input: "  hello there ",
+			want:  "  \x1b[1;104mhello there\x1b[0m ",
+		},
+		{
+			name:  "all attributes",
+			style: Style{Foreground: White, Background: Black, Bold: true, Italic: true, Underline: true, Dim: true},
+			input: "hello",
+			want:  "\x1b[1;2;3;4;37;40mhello\x1b[0m",
+		},
+		{
+			name:  "blank line",
+			style: Style{Foreground: Red},
+			input: "     ",
+			want:  "     ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.style.apply(tt.input, true); got != tt.want {
+				t.Fatalf("expected: %q, got: %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestStyle_apply_NoColor(t *testing.T) {
+	if got := (Style{Foreground: Red}).apply("hello", false); got != "hello" {
+		t.Fatalf("expected: %q, got: %q", "hello", got)
+	}
+}
+
+func TestColorEnabled(t *testing.T) {
+	defer func(mode ColorMode) { ColorOutput = mode }(ColorOutput)
+	null, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("could not open null device: %v", err)
+	}
+	defer null.Close()
+
+	tests := []struct {
+		name string
+		mode ColorMode
+		w    io.Writer
+		want bool
+	}{
+		{name: "auto buffer", mode: ColorAuto, w: &bytes.Buffer{}, want: false},
+		{name: "auto null device", mode: ColorAuto, w: null, want: false},
+		{name: "never", mode: ColorNever, w: null, want: false},
+		{name: "always", mode: ColorAlways, w: &bytes.Buffer{}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ColorOutput = tt.mode
+			if got := ColorEnabled(tt.w); got != tt.want {
+				t.Fatalf("expected: %v, got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTextTableFormatter_Output_Styles(t *testing.T) {
+	input := [][]string{
+		{"item", "qty"},
+		{"apple", "3"},
+		{"pear", "5"},
+		{"plum", "7"},
+	}
+	want := " \x1b[1mitem\x1b[0m   \x1b[1;33mqty\x1b[0m   \n" +
+		"              \n" +
+		" apple  \x1b[33m3\x1b[0m     \n" +
+		"              \n" +
+		" \x1b[2mpear\x1b[0m   \x1b[2;31m5\x1b[0m     \n" +
+		"              \n" +
+		" plum   \x1b[33m7\x1b[0m     \n" +
+		"              "
+
+	ttf := New(input, Config{
+		ColumnWidth:  5,
+		ColumnMargin: 1,
+		RowMargin:    1,
+		HeaderRows:   1,
+		ZebraStyles:  []Style{{}, {Dim: true}},
+		ColumnStyles: map[int]Style{1: {Foreground: Yellow}},
+		RowStyles:    map[int]Style{0: {Bold: true}},
+		CellStyles:   map[Cell]Style{{Row: 2, Column: 1}: {Foreground: Red}},
+		Color:        ColorAlways,
+	})
+
+	output, err := ttf.Output()
+	if err != nil {
+		t.Fatalf("failed to format text table: %v", err)
+	}
+
+	if output != want {
+		t.Fatalf("expected: \n%q\n\ngot: \n%q\n", want, output)
+	}
+}
+
+func TestTextTableFormatter_Output_Color(t *testing.T) {
+	input := [][]string{{"a"}}
+	tests := []struct {
+		name    string
+		color   ColorMode
+		writeTo bool
+		want    string
+	}{
+		{name: "auto", color: ColorAuto, want: " a  \n    "},
+		{name: "auto written to buffer", color: ColorAuto, writeTo: true, want: " a  \n    "},
+		{name: "always", color: ColorAlways, want: " \x1b[1ma\x1b[0m  \n    "},
+		{name: "always written to buffer", color: ColorAlways, writeTo: true, want: " \x1b[1ma\x1b[0m  \n    "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ttf := New(input, Config{ColumnWidth: 2, ColumnMargin: 1, Style: Style{Bold: true}, Color: tt.color})
+			var output string
+			if tt.writeTo {
+				var b bytes.Buffer
+				if _, err := ttf.WriteTo(&b); err != nil {
+					t.Fatalf("failed to write text table: %v", err)
+				}
+				output = b.String()
+			} else {
+				var err error
+				if output, err = ttf.Output(); err != nil {
+					t.Fatalf("failed to format text table: %v", err)
+				}
+			}
+			if output != tt.want {
+				t.Fatalf("expected: \n%q\n\ngot: \n%q\n", tt.want, output)
+			}
+		})
+	}
+}
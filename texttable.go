@@ -2,6 +2,7 @@ package texttable
 
 import (
 	"io"
+	"sort"
 	"strings"
 )
 
@@ -9,9 +10,11 @@ import (
 // into a constant width string table. It should be constructed
 // only with the New function.
 type TextTable struct {
-	scannerMatrix     [][]*LineScanner
+	textTable         [][]string
+	config            Config
 	emptyColumnFiller string
 	rowSpacing        string
+	color             bool
 }
 
 // Config should be used to set up configuration
@@ -21,12 +24,118 @@ type Config struct {
 	ColumnMargin   int
 	RowMargin      int
 	IgnoreNewLines bool
+
+	// ColumnWidths maps column indexes to the width of that
+	// column, overriding ColumnWidth. Widths below 1 are ignored.
+	ColumnWidths map[int]int
+
+	// PreserveWhiteSpace, TabWidth, LineBreaking and Hyphenator
+	// are passed to the LineScanner of every cell, see
+	// LineScannerConfig.
+	PreserveWhiteSpace bool
+	TabWidth           int
+	LineBreaking       LineBreaking
+	Hyphenator         *Hyphenator
+
+	// MaxWidth is the maximum width of an output line. Tables
+	// wider than MaxWidth are split into bands of columns which
+	// are output one after the other. A value of 0 disables
+	// the splitting.
+	MaxWidth int
+	// KeyColumns holds the indexes of the columns which are
+	// repeated in every band when the table is split.
+	KeyColumns []int
+
+	// HeaderRows is the number of rows at the top of the
+	// table which hold headings rather than values.
+	HeaderRows int
+	// Footers are output below the table, separated from it
+	// by a line.
+	Footers []Footer
+
+	// Formatters maps column indexes to the Formatter applied
+	// to the values of that column, before they are wrapped.
+	// Header rows are not formatted, and footer cells are only
+	// formatted for footers which are Formatted.
+	Formatters map[int]Formatter
+
+	// Alignments maps column indexes to the alignment of
+	// that column. Columns use AlignLeft by default, or
+	// AlignStart in RightToLeft tables.
+	Alignments map[int]Alignment
+	// DecimalSeparator is the character lined up in columns
+	// which use AlignDecimal. It defaults to '.'.
+	DecimalSeparator rune
+
+	// Title is output above the table, and Caption below it.
+	// Both are wrapped to the width of the table. In RightToLeft
+	// tables, AlignLeft, which is the default, is taken to be
+	// AlignStart for the title and caption.
+	Title            string
+	TitleAlignment   Alignment
+	Caption          string
+	CaptionAlignment Alignment
+
+	// Style is applied to every cell of the table. It is
+	// overlaid by ZebraStyles, which alternate over the rows
+	// after the header rows, then by ColumnStyles, RowStyles
+	// and CellStyles, in that order.
+	Style        Style
+	ZebraStyles  []Style
+	ColumnStyles map[int]Style
+	RowStyles    map[int]Style
+	CellStyles   map[Cell]Style
+	// Color sets when styles are output as ANSI escape
+	// codes. ColorAuto, the zero value, leaves it to
+	// ColorOutput.
+	Color ColorMode
+	// Rules are conditional formatting rules, whose styles
+	// are overlaid on the styles above, in order, for the
+	// cells they match. Header rows are not evaluated.
+	Rules []Rule
+
+	// Expanded outputs each row after the header rows as a
+	// record, with a line for each column holding the column
+	// heading and the value. The headings are taken from the
+	// first header row, or numbered if there are no header
+	// rows. Values keep the alignment of their column, and are
+	// wrapped to the width of the widest column. Footers are
+	// not output for expanded tables.
+	Expanded bool
+
+	// RightToLeft lays out the columns of the table from right
+	// to left, and makes the paragraphs of every cell, and of the
+	// title and caption, right-to-left, see DirectionRightToLeft.
+	RightToLeft bool
+
+	// Spans maps cells to the number of columns they span. A
+	// spanning cell is wrapped across the width of its columns,
+	// taking its alignment and style from its own column, and
+	// the cells it covers are not output. Spans are cut short
+	// at the edge of the table, and of each column band.
+	Spans map[Cell]int
+
+	// cellAlignments maps cells to their alignment, overriding
+	// the alignment of their column. It is used by records.
+	cellAlignments map[Cell]Alignment
+	// cellTexts maps cells to the text output in place of their
+	// formatted value, which the rules still match. It is used
+	// by diffs, whose changed cells show both of their values.
+	cellTexts map[Cell]string
 }
 
+// bandSpacing separates the column bands
+// of a table which has been split.
+const bandSpacing = "\n\n"
+
+// footerSeparator is repeated across the table
+// width to separate the footers from the table.
+const footerSeparator = "-"
+
 // New knows how to create a new TextTable
 // from a given text table and optional Config parameters.
-// Note: the minimum values for column width, column margin and row
-// margin are 1, 0 and 0 respectively.
+// Note: the minimum values for column width, column margin,
+// row margin and max width are 1, 0, 0 and 0 respectively.
 func New(textTable [][]string, config Config) *TextTable {
 	// adjust config to minimum threshold
 	// if any parameters go below.
@@ -39,6 +148,15 @@ func New(textTable [][]string, config Config) *TextTable {
 	if config.RowMargin < 0 {
 		config.RowMargin = 0
 	}
+	if config.MaxWidth < 0 {
+		config.MaxWidth = 0
+	}
+	if config.HeaderRows < 0 {
+		config.HeaderRows = 0
+	}
+	if config.DecimalSeparator == 0 {
+		config.DecimalSeparator = '.'
+	}
 
 	// ensure we have a constant width
 	// table by padding any rows with
@@ -57,62 +175,435 @@ func New(textTable [][]string, config Config) *TextTable {
 		}
 	}
 
-	// create line scanner matrix
-	height := len(textTable)
-	width := maxRowLength
-	scannerMatrix := make([][]*LineScanner, height)
-	for i := range scannerMatrix {
-		scannerMatrix[i] = make([]*LineScanner, width)
-	}
-	for y := range textTable {
-		for x := range textTable[y] {
-			scannerMatrix[y][x] = NewLineScanner(textTable[y][x], LineScannerConfig{
-				LineWidth:      config.ColumnWidth,
-				LineMargin:     config.ColumnMargin,
-				IgnoreNewLines: config.IgnoreNewLines,
-			})
-		}
-	}
-
 	return &TextTable{
-		scannerMatrix:     scannerMatrix,
+		textTable:         textTable,
+		config:            config,
 		rowSpacing:        strings.Repeat("\n", config.RowMargin),
 		emptyColumnFiller: strings.Repeat(" ", config.ColumnWidth+2*config.ColumnMargin),
+		color:             colorEnabled(config.Color, nil),
 	}
 }
 
-// Output produces the formatted text table as a string.
+// WriteTo writes the formatted text table to w, implementing
+// io.WriterTo. Unlike Output, styles are output as escape codes
+// with ColorAuto if w is a terminal.
+func (tf *TextTable) WriteTo(w io.Writer) (int64, error) {
+	out := *tf
+	out.color = colorEnabled(tf.config.Color, w)
+	stringTable, err := out.Output()
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.WriteString(w, stringTable)
+	return int64(n), err
+}
+
+// Output produces the formatted text table as a string. Styles
+// are output as escape codes as set by the Color of the Config,
+// see ColorAuto.
 func (tf *TextTable) Output() (string, error) {
+	var stringTable string
+	var err error
+
+	// text is the table whose width the title
+	// and caption are wrapped across
+	text := tf
+	bands := tf.columnBands()
+	if tf.config.Expanded {
+		// records are output as a header
+		// column and a value column
+		text = New(nil, tf.recordConfig())
+		bands = [][]int{{0, 1}}
+		stringTable, err = tf.outputRecords()
+	} else {
+		stringTable, err = tf.outputBands(bands)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if tf.config.Title != "" {
+		title, err := text.outputText(tf.config.Title, tf.config.TitleAlignment, bands)
+		if err != nil {
+			return "", err
+		}
+		stringTable = title + "\n\n" + stringTable
+	}
+	if tf.config.Caption != "" {
+		caption, err := text.outputText(tf.config.Caption, tf.config.CaptionAlignment, bands)
+		if err != nil {
+			return "", err
+		}
+		stringTable += "\n" + caption
+	}
+	return stringTable, nil
+}
+
+// Transpose swaps the rows and columns of the table. Any
+// configuration which refers to row or column indexes is
+// left as it is.
+func (tf *TextTable) Transpose() {
+	tf.textTable = Transpose(tf.textTable)
+}
+
+// outputBands produces the formatted text table, with
+// its footers, for each of the given column bands.
+func (tf *TextTable) outputBands(bands [][]int) (string, error) {
 	stringTable := ""
 
-	numRows := len(tf.scannerMatrix)
+	footerRows := tf.makeFooterRows()
+	decimalFields := tf.makeDecimalFields(tf.textTable, footerRows)
 
-	for n, scannerRow := range tf.scannerMatrix {
-		rowLength := len(scannerRow)
-		eofCount := 0
-		for i := 0; i < rowLength; i++ {
-			line, err := scannerRow[i].Next()
-			if err != nil && err != io.EOF {
+	for n, columns := range bands {
+		band, err := tf.outputRows(tf.textTable, columns, decimalFields, false)
+		if err != nil {
+			return "", err
+		}
+		if len(footerRows) > 0 {
+			footers, err := tf.outputRows(footerRows, columns, decimalFields, true)
+			if err != nil {
 				return "", err
 			}
-			if err == io.EOF {
-				stringTable += tf.emptyColumnFiller
-				eofCount++
-			} else {
-				stringTable += line
+			separator := strings.Repeat(footerSeparator, tf.bandWidth(columns))
+			band += "\n" + separator + "\n" + footers
+		}
+		if n > 0 {
+			stringTable += bandSpacing
+		}
+		stringTable += band
+	}
+	return stringTable, nil
+}
+
+// outputText wraps text, such as the title or caption,
+// across the width of the widest column band.
+func (tf *TextTable) outputText(text string, alignment Alignment, bands [][]int) (string, error) {
+	width := len(tf.emptyColumnFiller)
+	for _, columns := range bands {
+		if w := tf.bandWidth(columns); w > width {
+			width = w
+		}
+	}
+
+	if alignment == AlignLeft && tf.config.RightToLeft {
+		alignment = AlignStart
+	}
+	s := NewLineScanner(text, LineScannerConfig{
+		LineWidth:      width - 2*tf.config.ColumnMargin,
+		LineMargin:     tf.config.ColumnMargin,
+		IgnoreNewLines: tf.config.IgnoreNewLines,
+		Alignment:      alignment,
+		Direction:      tf.direction(),
+	})
+	var lines []string
+	for {
+		line, err := s.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// outputRows produces the formatted text table for the
+// given rows, using only the given column indexes. The
+// footer flag is set when the rows are the footers.
+func (tf *TextTable) outputRows(rows [][]string, columns []int, decimalFields map[int]decimalField, footer bool) (string, error) {
+	stringTable := ""
+
+	numRows := len(rows)
+	for y := range rows {
+		row, err := tf.outputRow(rows[y], y, columns, decimalFields, footer)
+		if err != nil {
+			return "", err
+		}
+		stringTable += row
+		if y != numRows-1 {
+			stringTable += tf.rowSpacing
+		}
+	}
+	return stringTable, nil
+}
+
+// outputRow produces the lines of row y of the table,
+// using only the given column indexes.
+func (tf *TextTable) outputRow(row []string, y int, columns []int, decimalFields map[int]decimalField, footer bool) (string, error) {
+	stringRow := ""
+
+	scannerRow := tf.makeScannerRow(row, y, columns, decimalFields, footer)
+	rowLength := len(scannerRow)
+	eofCount := 0
+	for i := 0; i < rowLength; i++ {
+		line, err := scannerRow[i].Next()
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+		if err == io.EOF {
+			stringRow += scannerRow[i].blank()
+			eofCount++
+		} else {
+			stringRow += line
+		}
+		if eofCount == rowLength {
+			break
+		}
+		if i == rowLength-1 {
+			eofCount = 0
+			i = -1
+			stringRow += "\n"
+		}
+	}
+	return stringRow, nil
+}
+
+// makeScannerRow creates a LineScanner for every cell of
+// row y of the table, using only the given column indexes,
+// in the order they are output. Cells covered by a spanning
+// cell have no LineScanner of their own.
+func (tf *TextTable) makeScannerRow(row []string, y int, columns []int, decimalFields map[int]decimalField, footer bool) []*LineScanner {
+	scannerRow := make([]*LineScanner, 0, len(columns))
+	for i := 0; i < len(columns); i++ {
+		x := columns[i]
+		span := 1
+		if !footer {
+			span = tf.span(y, columns[i:])
+		}
+		i += span - 1
+		lineWidth := tf.bandWidth(columns[i-span+1:i+1]) - 2*tf.config.ColumnMargin
+		text := row[x]
+		if !footer {
+			text = tf.cellText(text, y, x)
+		}
+		scanner := NewLineScanner(text, LineScannerConfig{
+			LineWidth:          lineWidth,
+			LineMargin:         tf.config.ColumnMargin,
+			IgnoreNewLines:     tf.config.IgnoreNewLines,
+			Alignment:          tf.cellAlignment(y, x),
+			PreserveWhiteSpace: tf.config.PreserveWhiteSpace,
+			TabWidth:           tf.config.TabWidth,
+			LineBreaking:       tf.config.LineBreaking,
+			Hyphenator:         tf.config.Hyphenator,
+			Direction:          tf.direction(),
+		})
+		scanner.style = tf.cellStyle(row[x], y, x, footer)
+		scanner.color = tf.color
+		if span == 1 {
+			scanner.decimal = decimalFields[x]
+		}
+		scannerRow = append(scannerRow, scanner)
+	}
+	if tf.config.RightToLeft {
+		for i, j := 0, len(scannerRow)-1; i < j; i, j = i+1, j-1 {
+			scannerRow[i], scannerRow[j] = scannerRow[j], scannerRow[i]
+		}
+	}
+	return scannerRow
+}
+
+// span returns the number of columns spanned by the cell in
+// row y of the first of the given columns, counting only the
+// columns which follow it in the table without a gap.
+func (tf *TextTable) span(y int, columns []int) int {
+	span := tf.config.Spans[Cell{Row: y, Column: columns[0]}]
+	n := 1
+	for n < span && n < len(columns) && columns[n] == columns[0]+n {
+		n++
+	}
+	return n
+}
+
+// direction returns the direction of the
+// paragraphs of the cells of the table.
+func (tf *TextTable) direction() Direction {
+	if tf.config.RightToLeft {
+		return DirectionRightToLeft
+	}
+	return DirectionAuto
+}
+
+// cellText returns the text of the cell in row y of the given
+// column, formatted by its Formatter unless it is a header, or
+// the text set for the cell in place of its value.
+func (tf *TextTable) cellText(value string, y, x int) string {
+	if text, ok := tf.config.cellTexts[Cell{Row: y, Column: x}]; ok {
+		return text
+	}
+	if y < tf.config.HeaderRows {
+		return value
+	}
+	if format := tf.config.Formatters[x]; format != nil {
+		return format(value)
+	}
+	return value
+}
+
+// cellAlignment returns the alignment of the cell in row y
+// of column x, which is the alignment of its column unless
+// it has its own.
+func (tf *TextTable) cellAlignment(y, x int) Alignment {
+	if alignment, ok := tf.config.cellAlignments[Cell{Row: y, Column: x}]; ok {
+		return alignment
+	}
+	return tf.columnAlignment(x)
+}
+
+// columnAlignment returns the alignment of column x, which
+// defaults to AlignStart in right-to-left tables.
+func (tf *TextTable) columnAlignment(x int) Alignment {
+	alignment, ok := tf.config.Alignments[x]
+	if !ok && tf.config.RightToLeft {
+		return AlignStart
+	}
+	return alignment
+}
+
+// cellStyle returns the style of a cell, which is the result
+// of overlaying the table, zebra, column, row and cell styles,
+// followed by the styles of the rules matching its raw value.
+// Footer cells only take the table and column styles, and
+// the styles of the rules.
+func (tf *TextTable) cellStyle(value string, y, x int, footer bool) Style {
+	style := tf.config.Style
+	header := !footer && y < tf.config.HeaderRows
+	if !footer && !header && len(tf.config.ZebraStyles) > 0 {
+		style = style.merge(tf.config.ZebraStyles[(y-tf.config.HeaderRows)%len(tf.config.ZebraStyles)])
+	}
+	style = style.merge(tf.config.ColumnStyles[x])
+	if !footer {
+		style = style.merge(tf.config.RowStyles[y])
+		style = style.merge(tf.config.CellStyles[Cell{Row: y, Column: x}])
+	}
+	if header {
+		return style
+	}
+	for _, rule := range tf.config.Rules {
+		if rule.Evaluate == nil || !rule.appliesTo(x) {
+			continue
+		}
+		if ruleStyle, ok := rule.Evaluate(value); ok {
+			style = style.merge(ruleStyle)
+		}
+	}
+	return style
+}
+
+// makeDecimalFields measures the widths either side of the
+// decimal separator for the values of every column which uses
+// AlignDecimal, in the rows of the table and in its footer rows,
+// which are already formatted. Values which would be wrapped
+// are not measured.
+func (tf *TextTable) makeDecimalFields(rows, footerRows [][]string) map[int]decimalField {
+	separator := string(tf.config.DecimalSeparator)
+	decimalFields := make(map[int]decimalField)
+	for x, alignment := range tf.config.Alignments {
+		if alignment != AlignDecimal {
+			continue
+		}
+		field := decimalField{separator: separator}
+		measure := func(text string) {
+			text = strings.TrimSpace(text)
+			i := strings.Index(text, separator)
+			if i < 0 || strings.ContainsAny(text, " \t\n") || displayWidth(text) > tf.columnWidth(x) {
+				return
 			}
-			if eofCount == rowLength {
-				break
+			if w := displayWidth(text[:i]); w > field.integerWidth {
+				field.integerWidth = w
 			}
-			if i == rowLength-1 {
-				eofCount = 0
-				i = -1
-				stringTable += "\n"
+			if w := displayWidth(text[i:]); w > field.fractionWidth {
+				field.fractionWidth = w
 			}
 		}
-		if n != numRows-1 {
-			stringTable += tf.rowSpacing
+		for y := range rows {
+			if x >= 0 && x < len(rows[y]) {
+				measure(tf.cellText(rows[y][x], y, x))
+			}
+		}
+		for y := range footerRows {
+			if x >= 0 && x < len(footerRows[y]) {
+				measure(footerRows[y][x])
+			}
+		}
+		if field.integerWidth+field.fractionWidth <= tf.columnWidth(x) {
+			decimalFields[x] = field
 		}
 	}
-	return stringTable, nil
+	return decimalFields
+}
+
+// columnBands splits the column indexes of the table into
+// bands which each fit within the configured max width. The
+// key columns are included in every band, and every band
+// holds at least one column which is not a key column, even
+// if that makes the band exceed the max width.
+func (tf *TextTable) columnBands() [][]int {
+	var width int
+	if len(tf.textTable) > 0 {
+		width = len(tf.textTable[0])
+	}
+	columns := make([]int, width)
+	for x := range columns {
+		columns[x] = x
+	}
+	if tf.config.MaxWidth == 0 || tf.bandWidth(columns) <= tf.config.MaxWidth {
+		return [][]int{columns}
+	}
+
+	isKey := make(map[int]bool)
+	var keys []int
+	for _, x := range tf.config.KeyColumns {
+		if x >= 0 && x < width && !isKey[x] {
+			isKey[x] = true
+			keys = append(keys, x)
+		}
+	}
+	keysWidth := tf.bandWidth(keys)
+
+	var bands [][]int
+	var band []int
+	for _, x := range columns {
+		if isKey[x] {
+			continue
+		}
+		if len(band) > 0 && keysWidth+tf.bandWidth(band)+tf.bandWidth([]int{x}) > tf.config.MaxWidth {
+			bands = append(bands, mergeColumns(keys, band))
+			band = nil
+		}
+		band = append(band, x)
+	}
+	if len(band) > 0 || len(bands) == 0 {
+		bands = append(bands, mergeColumns(keys, band))
+	}
+	return bands
+}
+
+// columnWidth returns the width of the text of a column.
+func (tf *TextTable) columnWidth(x int) int {
+	if w := tf.config.ColumnWidths[x]; w > 0 {
+		return w
+	}
+	return tf.config.ColumnWidth
+}
+
+// bandWidth returns the width of the given
+// columns, including their margins.
+func (tf *TextTable) bandWidth(columns []int) int {
+	width := 0
+	for _, x := range columns {
+		width += tf.columnWidth(x) + 2*tf.config.ColumnMargin
+	}
+	return width
+}
+
+// mergeColumns merges two sets of column indexes
+// into a new slice, sorted in table order.
+func mergeColumns(a, b []int) []int {
+	merged := make([]int, 0, len(a)+len(b))
+	merged = append(merged, a...)
+	merged = append(merged, b...)
+	sort.Ints(merged)
+	return merged
 }
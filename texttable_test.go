@@ -292,3 +292,345 @@ func TestTextTableFormatter_Output_IgnoreNewLines(t *testing.T) {
 	}
 
 }
+
+func TestTextTableFormatter_Output_ColumnBands(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      [][]string
+		keyColumns []int
+		want       string
+	}{
+		{
+			name: "key column repeated",
+			input: [][]string{
+				{"id", "name", "colour", "size"},
+				{"1", "apple", "red", "small"},
+				{"2", "banana", "yellow", "medium"},
+			},
+			keyColumns: []int{0},
+			want: ` id      name    colour 
+                        
+ 1       apple   red    
+                        
+ 2       banana  yellow 
+                        
+
+ id      size   
+                
+ 1       small  
+                
+ 2       medium 
+                `,
+		},
+		{
+			name: "no key columns",
+			input: [][]string{
+				{"id", "name", "colour", "size"},
+				{"1", "apple", "red", "small"},
+			},
+			want: ` id      name    colour 
+                        
+ 1       apple   red    
+                        
+
+ size   
+        
+ small  
+        `,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ttf := New(tt.input, Config{
+				ColumnWidth:  6,
+				ColumnMargin: 1,
+				RowMargin:    1,
+				MaxWidth:     24,
+				KeyColumns:   tt.keyColumns,
+			})
+
+			output, err := ttf.Output()
+			if err != nil {
+				t.Fatalf("failed to format text table: %v", err)
+			}
+
+			if output != tt.want {
+				t.Fatalf("expected: \n%v\n\ngot: \n%v\n", tt.want, output)
+			}
+		})
+	}
+}
+
+func TestTextTableFormatter_Output_DecimalAlignment(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     [][]string
+		separator rune
+		want      string
+	}{
+		{
+			name: "decimal point",
+			input: [][]string{
+				{"price"},
+				{"1.5"},
+				{"12.25"},
+				{"100"},
+				{"3.125"},
+			},
+			want: `    price 
+          
+    1.5   
+          
+   12.25  
+          
+      100 
+          
+    3.125 
+          `,
+		},
+		{
+			name: "decimal comma",
+			input: [][]string{
+				{"1,5"},
+				{"12,25"},
+			},
+			separator: ',',
+			want: `     1,5  
+          
+    12,25 
+          `,
+		},
+		{
+			name: "wide characters",
+			input: [][]string{
+				{"円1.5"},
+				{"12.25"},
+			},
+			want: `   円1.5  
+          
+    12.25 
+          `,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ttf := New(tt.input, Config{
+				ColumnWidth:      8,
+				ColumnMargin:     1,
+				RowMargin:        1,
+				Alignments:       map[int]Alignment{0: AlignDecimal},
+				DecimalSeparator: tt.separator,
+			})
+
+			output, err := ttf.Output()
+			if err != nil {
+				t.Fatalf("failed to format text table: %v", err)
+			}
+
+			if output != tt.want {
+				t.Fatalf("expected: \n%v\n\ngot: \n%v\n", tt.want, output)
+			}
+		})
+	}
+}
+
+func TestTextTableFormatter_Output_TitleAndCaption(t *testing.T) {
+	input := [][]string{
+		{"item", "qty"},
+		{"apple", "3"},
+	}
+	want := `    Fruit stock     
+
+ item      qty      
+                    
+ apple     3        
+                    
+ Counted on the     
+ morning of the     
+ 17th               `
+
+	ttf := New(input, Config{
+		ColumnWidth:      8,
+		ColumnMargin:     1,
+		RowMargin:        1,
+		Title:            "Fruit stock",
+		TitleAlignment:   AlignCenter,
+		Caption:          "Counted on the morning of the 17th",
+		CaptionAlignment: AlignLeft,
+	})
+
+	output, err := ttf.Output()
+	if err != nil {
+		t.Fatalf("failed to format text table: %v", err)
+	}
+
+	if output != want {
+		t.Fatalf("expected: \n%v\n\ngot: \n%v\n", want, output)
+	}
+}
+
+func TestTextTableFormatter_Output_Spans(t *testing.T) {
+	input := [][]string{
+		{"Quarterly sales by region", "", ""},
+		{"north", "south", "east"},
+		{"12", "7", "9"},
+	}
+	tests := []struct {
+		name   string
+		config Config
+		want   string
+	}{
+		{
+			name: "span",
+			config: Config{
+				ColumnWidth:  6,
+				ColumnMargin: 1,
+				RowMargin:    1,
+				Spans:        map[Cell]int{{Row: 0, Column: 0}: 3},
+			},
+			want: ` Quarterly sales by     
+ region                 
+                        
+ north   south   east   
+                        
+ 12      7       9      
+                        `,
+		},
+		{
+			name: "span cut short",
+			config: Config{
+				ColumnWidth:  6,
+				ColumnMargin: 1,
+				RowMargin:    1,
+				Spans:        map[Cell]int{{Row: 0, Column: 0}: 5},
+			},
+			want: ` Quarterly sales by     
+ region                 
+                        
+ north   south   east   
+                        
+ 12      7       9      
+                        `,
+		},
+		{
+			name: "span split into bands",
+			config: Config{
+				ColumnWidth:  6,
+				ColumnMargin: 1,
+				RowMargin:    1,
+				MaxWidth:     16,
+				Spans:        map[Cell]int{{Row: 0, Column: 0}: 3},
+			},
+			want: ` Quarterly      
+ sales by       
+ region         
+                
+ north   south  
+                
+ 12      7      
+                
+
+        
+ east   
+        
+ 9      
+        `,
+		},
+		{
+			name: "span right to left",
+			config: Config{
+				ColumnWidth:  6,
+				ColumnMargin: 1,
+				RowMargin:    1,
+				RightToLeft:  true,
+				Spans:        map[Cell]int{{Row: 0, Column: 0}: 2},
+			},
+			want: `              Quarterly 
+               sales by 
+                 region 
+                        
+   east   south   north 
+                        
+      9       7      12 
+                        `,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := New(input, tt.config).Output()
+			if err != nil {
+				t.Fatalf("failed to format text table: %v", err)
+			}
+			if output != tt.want {
+				t.Fatalf("expected: \n%v\n\ngot: \n%v\n", tt.want, output)
+			}
+		})
+	}
+}
+
+func TestTextTableFormatter_Output_ColumnWidths(t *testing.T) {
+	input := [][]string{
+		{"id", "description", "qty"},
+		{"1", "left-handed screwdriver", "12"},
+	}
+	tests := []struct {
+		name   string
+		config Config
+		want   string
+	}{
+		{
+			name: "widths",
+			config: Config{
+				ColumnWidth:  3,
+				ColumnMargin: 1,
+				RowMargin:    1,
+				ColumnWidths: map[int]int{1: 12},
+			},
+			want: ` id   description   qty 
+                        
+ 1    left-handed   12  
+      screwdriver       
+                        `,
+		},
+		{
+			name: "widths split into bands",
+			config: Config{
+				ColumnWidth:  3,
+				ColumnMargin: 1,
+				RowMargin:    1,
+				MaxWidth:     15,
+				ColumnWidths: map[int]int{1: 12},
+			},
+			want: ` id  
+     
+ 1   
+     
+
+ description  
+              
+ left-handed  
+ screwdriver  
+              
+
+ qty 
+     
+ 12  
+     `,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := New(input, tt.config).Output()
+			if err != nil {
+				t.Fatalf("failed to format text table: %v", err)
+			}
+			if output != tt.want {
+				t.Fatalf("expected: \n%v\n\ngot: \n%v\n", tt.want, output)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package texttable
+
+import "unicode"
+
+// breakClass is the line breaking class of a character, as
+// defined by the Unicode line breaking algorithm (UAX #14).
+// Only the classes which can occur inside a word, once it
+// has been split at white space, are distinguished.
+type breakClass int
+
+const (
+	classAL breakClass = iota // alphabetic, and anything unclassified
+	classBA                   // break after
+	classBB                   // break before
+	classCL                   // close punctuation
+	classCM                   // combining mark
+	classCP                   // close parenthesis
+	classEX                   // exclamation and interrogation
+	classGL                   // non-breaking glue
+	classHY                   // hyphen
+	classID                   // ideographic
+	classIN                   // inseparable
+	classIS                   // infix numeric separator
+	classNS                   // non-starter
+	classNU                   // numeric
+	classOP                   // open punctuation
+	classPO                   // postfix numeric
+	classPR                   // prefix numeric
+	classQU                   // quotation
+	classSY                   // symbols allowing a break after
+	classWJ                   // word joiner
+	classZW                   // zero width space
+)
+
+// lineBreakClass returns the line breaking class of a rune.
+func lineBreakClass(r rune) breakClass {
+	switch r {
+	case '\u200b':
+		return classZW
+	case '\u2060', '\ufeff':
+		return classWJ
+	case '\u00a0', '\u034f', '\u2007', '\u2011', '\u202f', '\u0f0c':
+		return classGL
+	case '-':
+		return classHY
+	case '/':
+		return classSY
+	case '\u00ad', '\u058a', '\u2010', '\u2012', '\u2013', '\u2014', '\u2027', '|':
+		return classBA
+	case '\u00b4', '\u02c8', '\u02cc':
+		return classBB
+	case '!', '?', '\u203d', '\uff01', '\uff1f':
+		return classEX
+	case ',', '.', ':', ';', '\u037e', '\u0589', '\u060c', '\u2044':
+		return classIS
+	case '(', '[':
+		return classOP
+	case ')', ']':
+		return classCP
+	case '\u3001', '\u3002', '\uff0c', '\uff0e', '\uff61', '\uff64':
+		return classCL
+	case '"', '\'':
+		return classQU
+	case '%', '\u00a2', '\u00b0', '\u2030', '\u2031', '\u2032', '\u2033', '\u2103', '\u2109', '\uff05', '\uffe0':
+		return classPO
+	case '+', '\\', '\u00b1', '\u2116', '\u2212', '\u2213':
+		return classPR
+	case '\u2024', '\u2025', '\u2026', '\u22ef', '\ufe19':
+		return classIN
+	case '\u200d':
+		return classCM
+	}
+	switch {
+	case isNonStarter(r):
+		return classNS
+	case unicode.In(r, unicode.Mn, unicode.Mc, unicode.Me):
+		return classCM
+	case unicode.Is(unicode.Nd, r):
+		return classNU
+	case unicode.Is(unicode.Ps, r):
+		return classOP
+	case unicode.Is(unicode.Pe, r):
+		return classCL
+	case unicode.In(r, unicode.Pi, unicode.Pf):
+		return classQU
+	case unicode.Is(unicode.Sc, r):
+		return classPR
+	case isWide(r):
+		return classID
+	}
+	return classAL
+}
+
+// isNonStarter reports whether a rune is one of the characters,
+// such as small kana and iteration marks, which a line should
+// not start with.
+func isNonStarter(r rune) bool {
+	switch r {
+	case '\u203c', '\u2047', '\u2048', '\u2049', '\u3005', '\u301c', '\u303b',
+		'\u309b', '\u309c', '\u309d', '\u309e', '\u30a0', '\u30fb', '\u30fc',
+		'\u30fd', '\u30fe', '\uff1a', '\uff1b', '\uff65', '\uff70':
+		return true
+	case '\u3041', '\u3043', '\u3045', '\u3047', '\u3049', '\u3063', '\u3083',
+		'\u3085', '\u3087', '\u308e', '\u3095', '\u3096', '\u30a1', '\u30a3',
+		'\u30a5', '\u30a7', '\u30a9', '\u30c3', '\u30e3', '\u30e5', '\u30e7',
+		'\u30ee', '\u30f5', '\u30f6':
+		// small hiragana and katakana
+		return true
+	}
+	return '\u31f0' <= r && r <= '\u31ff'
+}
+
+// lineBreaks returns the byte offsets in a word before which
+// a line may be broken, following the pair rules of UAX #14.
+// The word is expected to hold no white space, so the rules
+// which deal with spaces and mandatory breaks are left out.
+func lineBreaks(word string) []int {
+	var breaks []int
+	var classes []breakClass
+	for i, r := range word {
+		class := lineBreakClass(r)
+		// combining marks take the class of the character they
+		// follow, and are never broken from it (LB9 and LB10).
+		if class == classCM {
+			if len(classes) > 0 && classes[len(classes)-1] != classZW {
+				continue
+			}
+			class = classAL
+		}
+		classes = append(classes, class)
+		if len(classes) > 1 && breakAllowed(classes, len(classes)-1) {
+			breaks = append(breaks, i)
+		}
+	}
+	return breaks
+}
+
+// breakAllowed reports whether a line may be broken
+// between the characters at i-1 and i.
+func breakAllowed(classes []breakClass, i int) bool {
+	before, after := classes[i-1], classes[i]
+	switch {
+	// LB8: break after zero width spaces.
+	case before == classZW:
+		return true
+	// LB7: do not break before zero width spaces.
+	case after == classZW:
+		return false
+	// LB11: do not break around word joiners.
+	case before == classWJ || after == classWJ:
+		return false
+	// LB12 and LB12a: do not break around glue,
+	// unless it follows a hyphen.
+	case before == classGL:
+		return false
+	case after == classGL && before != classBA && before != classHY:
+		return false
+	// LB13: do not break before closing punctuation,
+	// exclamation marks, separators or '/'.
+	case after == classCL || after == classCP || after == classEX ||
+		after == classIS || after == classSY:
+		return false
+	// LB14: do not break after opening punctuation.
+	case before == classOP:
+		return false
+	// LB15 and LB16.
+	case before == classQU && after == classOP:
+		return false
+	case (before == classCL || before == classCP) && after == classNS:
+		return false
+	// LB19: do not break around quotation marks.
+	case before == classQU || after == classQU:
+		return false
+	// LB20a: do not break after a word initial hyphen,
+	// nor, going beyond UAX #14, after the slash which
+	// starts an absolute path.
+	case i == 1 && (before == classHY || before == classBA || before == classSY) && after == classAL:
+		return false
+	// LB21 and LB22: do not break before hyphens,
+	// non-starters and inseparable characters.
+	case after == classBA || after == classHY || after == classNS ||
+		after == classIN || before == classBB:
+		return false
+	// LB23 and LB23a: letters and numbers, and
+	// ideographs with their currency signs.
+	case before == classAL && after == classNU,
+		before == classNU && after == classAL,
+		before == classPR && after == classID,
+		before == classID && after == classPO:
+		return false
+	// LB24: prefixes and postfixes with letters.
+	case (before == classPR || before == classPO) && after == classAL,
+		before == classAL && (after == classPR || after == classPO):
+		return false
+	// LB25: do not break inside numbers.
+	case (before == classPR || before == classPO || before == classOP ||
+		before == classHY) && after == classNU,
+		before == classNU && (after == classNU || after == classPO || after == classPR),
+		(before == classSY || before == classIS) && after == classNU && followsNumber(classes, i-1):
+		return false
+	// LB28 and LB29: do not break between letters,
+	// or after a separator followed by a letter.
+	case before == classAL && after == classAL,
+		before == classIS && after == classAL:
+		return false
+	// LB30: do not break between letters and parentheses.
+	case (before == classAL || before == classNU) && after == classOP,
+		before == classCP && (after == classAL || after == classNU):
+		return false
+	}
+	// LB31: break everywhere else.
+	return true
+}
+
+// followsNumber reports whether the numeric separators
+// ending at i follow a number.
+func followsNumber(classes []breakClass, i int) bool {
+	for ; i >= 0; i-- {
+		switch classes[i] {
+		case classSY, classIS:
+			continue
+		case classNU:
+			return true
+		}
+		return false
+	}
+	return false
+}
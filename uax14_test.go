@@ -0,0 +1,93 @@
+package texttable
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestLineBreaks(t *testing.T) {
+	tests := []struct {
+		name string
+		word string
+		want []int
+	}{
+		{name: "letters", word: "hello", want: nil},
+		{name: "hyphen", word: "well-known", want: []int{5}},
+		{name: "word initial hyphen", word: "-known", want: nil},
+		{name: "negative number", word: "-5", want: nil},
+		{name: "slashes", word: "usr/local/bin", want: []int{4, 10}},
+		{name: "absolute path", word: "/usr/bin", want: []int{5}},
+		{name: "fraction", word: "1/2", want: nil},
+		{name: "decimal", word: "3.14", want: nil},
+		{name: "url", word: "https://a.io/b", want: []int{8, 13}},
+		{name: "zero width space", word: "ab\u200bcd", want: []int{5}},
+		{name: "soft hyphen", word: "ab\u00adcd", want: []int{4}},
+		{name: "no break space", word: "ab\u00a0cd", want: nil},
+		{name: "ideographs", word: "中文字", want: []int{3, 6}},
+		{name: "closing punctuation", word: "字、字", want: []int{6}},
+		{name: "small kana", word: "キャッ", want: nil},
+		{name: "parentheses", word: "(字)", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lineBreaks(tt.word); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lineBreaks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLineScanner_Next_BreakOpportunities(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "hyphens and slashes",
+			input: "a well-known path /usr/local/bin",
+			want: `a well-  
+known    
+path     
+/usr/    
+local/bin
+`,
+		},
+		{
+			name:  "ideographs",
+			input: "日本語のテキストは、スペースなし",
+			want: `日本語の 
+テキスト 
+は、ス   
+ペースな 
+し       
+`,
+		},
+		{
+			name:  "mixed scripts",
+			input: "see 中文字符测试",
+			want: `see 中文 
+字符测试 
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formattedText := ""
+			s := NewLineScanner(tt.input, LineScannerConfig{LineWidth: 9})
+			for {
+				line, err := s.Next()
+				if err == io.EOF {
+					break
+				}
+				formattedText += line + "\n"
+			}
+			if formattedText != tt.want {
+				t.Fatalf("expected: \n%v\n\ngot: \n%v\n", tt.want, formattedText)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package texttable
+
+import "unicode"
+
+// wideRanges holds the main ranges of characters which are
+// wide or full width in East Asian text (UAX #11), and take
+// up two columns of a terminal.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115f},   // Hangul Jamo initial consonants
+	{0x231a, 0x231b},   // watch and hourglass
+	{0x2e80, 0x303e},   // CJK radicals and punctuation
+	{0x3041, 0x33ff},   // kana, Bopomofo and CJK compatibility
+	{0x3400, 0x4dbf},   // CJK unified ideographs extension A
+	{0x4e00, 0x9fff},   // CJK unified ideographs
+	{0xa000, 0xa4cf},   // Yi
+	{0xa960, 0xa97f},   // Hangul Jamo extended A
+	{0xac00, 0xd7a3},   // Hangul syllables
+	{0xf900, 0xfaff},   // CJK compatibility ideographs
+	{0xfe10, 0xfe19},   // vertical forms
+	{0xfe30, 0xfe6f},   // CJK compatibility and small forms
+	{0xff00, 0xff60},   // full width forms
+	{0xffe0, 0xffe6},   // full width signs
+	{0x1f300, 0x1f64f}, // pictographs and emoticons
+	{0x1f900, 0x1f9ff}, // supplemental pictographs
+	{0x20000, 0x2fffd}, // CJK unified ideographs extension B onwards
+	{0x30000, 0x3fffd}, // CJK unified ideographs extension G onwards
+}
+
+// isWide reports whether a rune takes up two columns.
+func isWide(r rune) bool {
+	if r < wideRanges[0][0] {
+		return false
+	}
+	for _, wide := range wideRanges {
+		if wide[0] <= r && r <= wide[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// runeWidth returns the number of columns a rune takes up
+// when displayed. Combining marks and format characters,
+// such as zero width spaces, take up none.
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x300:
+		if r == '\u00ad' {
+			return 0
+		}
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case isWide(r):
+		return 2
+	}
+	return 1
+}
+
+// displayWidth returns the number of columns
+// a string takes up when displayed.
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		width += runeWidth(r)
+	}
+	return width
+}
+
+// cutWidth cuts a string into a head which takes up at most
+// width columns, and the rest. At least one rune is always
+// cut, so that a wide rune still moves on to the next line
+// of a narrow column.
+func cutWidth(s string, width int) (head, tail string) {
+	w := 0
+	for i, r := range s {
+		w += runeWidth(r)
+		if w > width && i > 0 {
+			return s[:i], s[i:]
+		}
+	}
+	return s, ""
+}
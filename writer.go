@@ -0,0 +1,121 @@
+package texttable
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+var errWriterClosed = errors.New("write to closed table writer")
+
+// Writer writes a text table to an io.Writer one row at a
+// time, so that tables too large to hold in memory can be
+// output as their rows are read. The output of a Writer is
+// the same as the output of a TextTable holding the same rows,
+// except that the table is never split into column bands, and
+// footers and the decimal alignment of columns are not output,
+// as each needs every row of the table. Writer should only be
+// constructed with the NewWriter function.
+type Writer struct {
+	w       io.Writer
+	tf      *TextTable
+	columns []int
+	rows    int
+	closed  bool
+}
+
+// NewWriter creates a Writer for a table with the given
+// number of columns, which writes to w. The Config is used
+// as it is by New, apart from the settings which a Writer
+// does not support. Styles are output as escape codes with
+// ColorAuto if w is a terminal.
+func NewWriter(w io.Writer, columns int, config Config) *Writer {
+	tw := &Writer{
+		w:       w,
+		tf:      New(nil, config),
+		columns: make([]int, columns),
+	}
+	tw.tf.color = colorEnabled(config.Color, w)
+	for x := range tw.columns {
+		tw.columns[x] = x
+	}
+	return tw
+}
+
+// WriteRow formats the next row of the table and writes it.
+// Rows with fewer cells than the table has columns are padded
+// with empty cells. The title of the table is written before
+// the first row.
+func (tw *Writer) WriteRow(row []string) error {
+	if tw.closed {
+		return errWriterClosed
+	}
+	if len(row) > len(tw.columns) {
+		return fmt.Errorf("row %v has %v cells, but the table has %v columns", tw.rows, len(row), len(tw.columns))
+	}
+	if len(row) < len(tw.columns) {
+		padded := make([]string, len(tw.columns))
+		copy(padded, row)
+		row = padded
+	}
+
+	prefix := tw.tf.rowSpacing
+	if tw.rows == 0 {
+		title, err := tw.title()
+		if err != nil {
+			return err
+		}
+		prefix = title
+	}
+	stringRow, err := tw.tf.outputRow(row, tw.rows, tw.columns, nil, false)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(tw.w, prefix+stringRow); err != nil {
+		return err
+	}
+	tw.rows++
+	return nil
+}
+
+// Close writes the caption of the table, if it has one,
+// and the title, if no rows were written. Rows can not be
+// written once the Writer is closed. It does not close the
+// underlying io.Writer.
+func (tw *Writer) Close() error {
+	if tw.closed {
+		return nil
+	}
+	tw.closed = true
+	if tw.rows == 0 {
+		title, err := tw.title()
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(tw.w, title); err != nil {
+			return err
+		}
+	}
+	if tw.tf.config.Caption == "" {
+		return nil
+	}
+	caption, err := tw.tf.outputText(tw.tf.config.Caption, tw.tf.config.CaptionAlignment, [][]int{tw.columns})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(tw.w, "\n"+caption)
+	return err
+}
+
+// title returns the title of the table and the blank
+// line which follows it, or nothing if it has no title.
+func (tw *Writer) title() (string, error) {
+	if tw.tf.config.Title == "" {
+		return "", nil
+	}
+	title, err := tw.tf.outputText(tw.tf.config.Title, tw.tf.config.TitleAlignment, [][]int{tw.columns})
+	if err != nil {
+		return "", err
+	}
+	return title + "\n\n", nil
+}
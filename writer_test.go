@@ -0,0 +1,97 @@
+package texttable
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriter_WriteRow(t *testing.T) {
+	input := [][]string{
+		{"name", "notes"},
+		{"Alice", "likes long walks on the beach"},
+		{"Bob"},
+		{"Summary spanning both columns"},
+	}
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{
+			name:   "plain",
+			config: Config{ColumnWidth: 8},
+		},
+		{
+			name: "margins, title and caption",
+			config: Config{
+				ColumnWidth:  8,
+				ColumnMargin: 1,
+				RowMargin:    1,
+				Title:        "People",
+				Caption:      "Two people",
+			},
+		},
+		{
+			name: "styles and spans",
+			config: Config{
+				ColumnWidth:  10,
+				ColumnMargin: 1,
+				RowMargin:    1,
+				HeaderRows:   1,
+				ZebraStyles:  []Style{{Bold: true}, {}},
+				Spans:        map[Cell]int{{Row: 3, Column: 0}: 2},
+				ColumnWidths: map[int]int{1: 14},
+			},
+		},
+		{
+			name: "right to left",
+			config: Config{
+				ColumnWidth:  8,
+				ColumnMargin: 1,
+				RowMargin:    1,
+				RightToLeft:  true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := make([][]string, len(input))
+			for y, row := range input {
+				rows[y] = append([]string(nil), row...)
+			}
+			want, err := New(rows, tt.config).Output()
+			if err != nil {
+				t.Fatalf("failed to format text table: %v", err)
+			}
+
+			var b strings.Builder
+			tw := NewWriter(&b, 2, tt.config)
+			for _, row := range input {
+				if err := tw.WriteRow(row); err != nil {
+					t.Fatalf("failed to write row: %v", err)
+				}
+			}
+			if err := tw.Close(); err != nil {
+				t.Fatalf("failed to close writer: %v", err)
+			}
+
+			if got := b.String(); got != want {
+				t.Fatalf("expected: \n%v\n\ngot: \n%v\n", want, got)
+			}
+		})
+	}
+}
+
+func TestWriter_WriteRow_Errors(t *testing.T) {
+	var b strings.Builder
+	tw := NewWriter(&b, 1, Config{ColumnWidth: 4})
+	if err := tw.WriteRow([]string{"a", "b"}); err == nil {
+		t.Fatalf("expected an error for a row with too many cells")
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("failed to close writer: %v", err)
+	}
+	if err := tw.WriteRow([]string{"a"}); err != errWriterClosed {
+		t.Fatalf("expected: \n%v\n\ngot: \n%v\n", errWriterClosed, err)
+	}
+}
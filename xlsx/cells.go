@@ -0,0 +1,145 @@
+package xlsx
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// The types of cells, set by their t attribute.
+// Cells without a type are numbers.
+const (
+	cellTypeSharedString  = "s"
+	cellTypeInlineString  = "inlineStr"
+	cellTypeFormulaString = "str"
+	cellTypeBoolean       = "b"
+	cellTypeError         = "e"
+	cellTypeNumber        = "n"
+	cellTypeDate          = "d"
+)
+
+const (
+	defaultTrueText  = "TRUE"
+	defaultFalseText = "FALSE"
+)
+
+// cellReader knows how to write the value of a cell
+// as text, according to its type and number format.
+type cellReader struct {
+	lookup    SharedStringLookup
+	trueText  string
+	falseText string
+	errorText map[string]string
+
+	// formats holds the number format of each cell
+	// format, and date1904 is set when dates count
+	// from 1904 rather than 1900.
+	formats  []*numberFormat
+	date1904 bool
+}
+
+// newCellReader creates a cellReader which looks up shared
+// strings in the given lookup, and writes boolean and error
+// cells as set by the config.
+func newCellReader(lookup SharedStringLookup, config Config) *cellReader {
+	cr := &cellReader{
+		lookup:    lookup,
+		trueText:  config.TrueText,
+		falseText: config.FalseText,
+		errorText: config.ErrorText,
+	}
+	if cr.trueText == "" {
+		cr.trueText = defaultTrueText
+	}
+	if cr.falseText == "" {
+		cr.falseText = defaultFalseText
+	}
+	return cr
+}
+
+// text returns the text of a cell.
+func (cr *cellReader) text(c cell) (string, error) {
+	switch c.Type {
+	case cellTypeSharedString:
+		stringIdx, err := strconv.Atoi(c.Value)
+		if err != nil {
+			return "", fmt.Errorf("could not parse string index %v: %v", c.Value, err)
+		}
+		if stringIdx < 0 || stringIdx >= len(cr.lookup) {
+			return "", fmt.Errorf("string index %v out of range", stringIdx)
+		}
+		return cr.formatText(c, cr.lookup[stringIdx]), nil
+	case cellTypeInlineString:
+		return cr.formatText(c, c.InlineString.text()), nil
+	case cellTypeFormulaString:
+		return cr.formatText(c, c.Value), nil
+	case cellTypeBoolean:
+		switch c.Value {
+		case "1", "true":
+			return cr.trueText, nil
+		case "0", "false":
+			return cr.falseText, nil
+		}
+		return c.Value, nil
+	case cellTypeError:
+		if text, ok := cr.errorText[c.Value]; ok {
+			return text, nil
+		}
+		return c.Value, nil
+	case cellTypeNumber, "":
+		format := cr.format(c)
+		if format == nil {
+			return c.Value, nil
+		}
+		value, err := strconv.ParseFloat(c.Value, 64)
+		if err != nil {
+			return c.Value, nil
+		}
+		if text, ok := format.formatNumber(value, cr.date1904); ok {
+			return text, nil
+		}
+		return c.Value, nil
+	case cellTypeDate:
+		format := cr.format(c)
+		if format == nil {
+			return c.Value, nil
+		}
+		if text, ok := formatDateValue(format, c.Value, cr.date1904); ok {
+			return text, nil
+		}
+		return c.Value, nil
+	}
+	// cells of a type which is not known are
+	// written as their raw value
+	return c.Value, nil
+}
+
+// format returns the number format of a cell, which is nil
+// when the workbook has no styles, or when the style of the
+// cell is not a valid index, so that the raw value of the cell
+// is written. Cells without a style use the first cell format.
+func (cr *cellReader) format(c cell) *numberFormat {
+	if len(cr.formats) == 0 {
+		return nil
+	}
+	if c.S == "" {
+		return cr.formats[0]
+	}
+	styleIdx, err := strconv.Atoi(c.S)
+	if err != nil || styleIdx < 0 || styleIdx >= len(cr.formats) {
+		return nil
+	}
+	return cr.formats[styleIdx]
+}
+
+// formatText applies the text section of the number format
+// of a cell to its text, if the format has one.
+func (cr *cellReader) formatText(c cell, text string) string {
+	format := cr.format(c)
+	if format == nil {
+		return text
+	}
+	if formatted, ok := format.formatText(text); ok {
+		return formatted
+	}
+	return text
+}
@@ -0,0 +1,102 @@
+package xlsx
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+const (
+	// defaultBaseColWidth is the base column width of work
+	// sheets which set neither a default nor a base width.
+	defaultBaseColWidth = 8
+	// colWidthPadding is added to the base column width to
+	// give the default column width, for the padding Excel
+	// puts either side of the text of a cell, of 5 pixels at
+	// a maximum digit width of 7 pixels.
+	colWidthPadding = 5.0 / 7.0
+)
+
+// sheetColumnWidths returns the width of each of the first n
+// columns of a work sheet, in characters, leaving out the
+// excluded columns. Columns without a width of their own take
+// the default width of the sheet.
+func sheetColumnWidths(sheet WorkSheet, n int, excluded columnRanges) ([]float64, error) {
+	defaultWidth := defaultBaseColWidth + colWidthPadding
+	switch format := sheet.SheetFormat; {
+	case format.DefaultColWidth != "":
+		w, err := strconv.ParseFloat(format.DefaultColWidth, 64)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse default column width %v: %v", format.DefaultColWidth, err)
+		}
+		defaultWidth = w
+	case format.BaseColWidth != "":
+		w, err := strconv.ParseFloat(format.BaseColWidth, 64)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse base column width %v: %v", format.BaseColWidth, err)
+		}
+		defaultWidth = w + colWidthPadding
+	}
+
+	widths := make([]float64, n)
+	for x := range widths {
+		widths[x] = defaultWidth
+	}
+	for _, c := range sheet.Cols {
+		if c.Width == "" {
+			continue
+		}
+		r, err := parseColRange(c)
+		if err != nil {
+			return nil, err
+		}
+		w, err := strconv.ParseFloat(c.Width, 64)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse column width %v: %v", c.Width, err)
+		}
+		for x := r.first; x <= r.last && x < n; x++ {
+			widths[x] = w
+		}
+	}
+
+	kept := make([]float64, 0, n)
+	for x, w := range widths {
+		if !excluded.contains(x) {
+			kept = append(kept, w)
+		}
+	}
+	return kept, nil
+}
+
+// scaleColumnWidths turns column widths in characters into the
+// widths of the columns of a text table. If tableWidth is set,
+// the widths are scaled so that the table, with the margins of
+// its columns, is that wide. Every column is at least 1 wide.
+func scaleColumnWidths(widths []float64, tableWidth, margin int) map[int]int {
+	scale := 1.0
+	if tableWidth > 0 {
+		total := 0.0
+		for _, w := range widths {
+			total += w
+		}
+		textWidth := tableWidth - len(widths)*2*margin
+		if total > 0 && textWidth > 0 {
+			scale = float64(textWidth) / total
+		}
+	}
+
+	// the rounding error is carried from column to column,
+	// so that the widths add up to the scaled total.
+	scaled := make(map[int]int, len(widths))
+	carry := 0.0
+	for x, w := range widths {
+		exact := w*scale + carry
+		width := int(math.Round(exact))
+		if width < 1 {
+			width = 1
+		}
+		carry = exact - float64(width)
+		scaled[x] = width
+	}
+	return scaled
+}
@@ -0,0 +1,98 @@
+package xlsx
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+)
+
+func TestSheetColumnWidths(t *testing.T) {
+	tests := []struct {
+		name     string
+		sheetXML string
+		n        int
+		excluded columnRanges
+		want     []float64
+	}{
+		{
+			name:     "column widths",
+			sheetXML: `<worksheet><sheetFormatPr defaultColWidth="10"/><cols><col min="1" max="1" width="20"/><col min="3" max="4" width="5.5"/></cols></worksheet>`,
+			n:        5,
+			want:     []float64{20, 10, 5.5, 5.5, 10},
+		},
+		{
+			name:     "base width",
+			sheetXML: `<worksheet><sheetFormatPr baseColWidth="10"/><cols><col min="2" max="2" width="20"/></cols></worksheet>`,
+			n:        2,
+			want:     []float64{10 + colWidthPadding, 20},
+		},
+		{
+			name:     "no widths",
+			sheetXML: `<worksheet/>`,
+			n:        1,
+			want:     []float64{8 + colWidthPadding},
+		},
+		{
+			name:     "excluded columns",
+			sheetXML: `<worksheet><sheetFormatPr defaultColWidth="10"/><cols><col min="1" max="2" width="20"/></cols></worksheet>`,
+			n:        3,
+			excluded: columnRanges{{first: 1, last: 1}},
+			want:     []float64{20, 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sheet WorkSheet
+			if err := xml.Unmarshal([]byte(tt.sheetXML), &sheet); err != nil {
+				t.Fatalf("could not decode work sheet: %v", err)
+			}
+			got, err := sheetColumnWidths(sheet, tt.n, tt.excluded)
+			if err != nil {
+				t.Fatalf("could not read column widths: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected: \n%v\n\ngot: \n%v\n", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestScaleColumnWidths(t *testing.T) {
+	tests := []struct {
+		name       string
+		widths     []float64
+		tableWidth int
+		margin     int
+		want       map[int]int
+	}{
+		{
+			name:   "unscaled",
+			widths: []float64{8.43, 20.7, 0.5},
+			want:   map[int]int{0: 8, 1: 21, 2: 1},
+		},
+		{
+			name:       "scaled",
+			widths:     []float64{10, 20, 10},
+			tableWidth: 46,
+			margin:     1,
+			want:       map[int]int{0: 10, 1: 20, 2: 10},
+		},
+		{
+			name:       "scaled down",
+			widths:     []float64{41.3, 11.3, 12.3},
+			tableWidth: 40,
+			margin:     1,
+			want:       map[int]int{0: 22, 1: 6, 2: 6},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := scaleColumnWidths(tt.widths, tt.tableWidth, tt.margin)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected: \n%v\n\ngot: \n%v\n", tt.want, got)
+			}
+		})
+	}
+}
@@ -20,13 +20,107 @@ type override struct {
 	PartName    string   `xml:"PartName,attr"`
 }
 
+// Workbook represents the workbook XML file of an
+// XLSX document, which lists its work sheets.
+type Workbook struct {
+	XMLName    xml.Name        `xml:"workbook"`
+	Properties workbookPr      `xml:"workbookPr"`
+	Sheets     []workbookSheet `xml:"sheets>sheet"`
+}
+
+// workbookPr contains the properties of a workbook,
+// such as the date system its dates count from.
+type workbookPr struct {
+	Date1904 string `xml:"date1904,attr"`
+}
+
+// workbookSheet contains the name, ID and state of
+// a work sheet, and the ID of the relationship which
+// holds the location of its file.
+type workbookSheet struct {
+	Name    string `xml:"name,attr"`
+	SheetID string `xml:"sheetId,attr"`
+	State   string `xml:"state,attr"`
+	RelID   string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+}
+
+// Relationships represents a relationships XML
+// file, which links a file to the files it uses.
+type Relationships struct {
+	XMLName       xml.Name       `xml:"Relationships"`
+	Relationships []relationship `xml:"Relationship"`
+}
+
+// relationship contains the type and
+// location of a related file.
+type relationship struct {
+	ID     string `xml:"Id,attr"`
+	Type   string `xml:"Type,attr"`
+	Target string `xml:"Target,attr"`
+}
+
+// StyleSheet represents the styles XML file of an XLSX
+// document, which holds the formats used by cells.
+type StyleSheet struct {
+	XMLName xml.Name `xml:"styleSheet"`
+	NumFmts []numFmt `xml:"numFmts>numFmt"`
+	CellXfs []xf     `xml:"cellXfs>xf"`
+}
+
+// numFmt is a number format defined by
+// the workbook, rather than built in.
+type numFmt struct {
+	NumFmtID   string `xml:"numFmtId,attr"`
+	FormatCode string `xml:"formatCode,attr"`
+}
+
+// xf is a cell format, which the s
+// attribute of a cell refers to by index.
+type xf struct {
+	NumFmtID string `xml:"numFmtId,attr"`
+}
+
 // WorkSheet represents an XLSX worksheet.
 type WorkSheet struct {
-	XMLName   xml.Name  `xml:"worksheet"`
-	Text      string    `xml:",chardata"`
-	Xmlns     string    `xml:"xmlns,attr"`
-	R         string    `xml:"r,attr"`
-	SheetData sheetData `xml:"sheetData"`
+	XMLName     xml.Name      `xml:"worksheet"`
+	Text        string        `xml:",chardata"`
+	Xmlns       string        `xml:"xmlns,attr"`
+	R           string        `xml:"r,attr"`
+	Dimension   dimension     `xml:"dimension"`
+	SheetFormat sheetFormatPr `xml:"sheetFormatPr"`
+	Cols        []col         `xml:"cols>col"`
+	SheetData   sheetData     `xml:"sheetData"`
+	MergeCells  []mergeCell   `xml:"mergeCells>mergeCell"`
+}
+
+// dimension holds the range of the used
+// cells of a work sheet, such as "A1:F20".
+type dimension struct {
+	Ref string `xml:"ref,attr"`
+}
+
+// sheetFormatPr holds the default width of the columns
+// of a work sheet, in characters. Work sheets which do
+// not set DefaultColWidth derive it from BaseColWidth.
+type sheetFormatPr struct {
+	DefaultColWidth string `xml:"defaultColWidth,attr"`
+	BaseColWidth    string `xml:"baseColWidth,attr"`
+}
+
+// col holds the properties of the columns from
+// Min to Max, which count from 1. Width is in
+// characters.
+type col struct {
+	Min    string `xml:"min,attr"`
+	Max    string `xml:"max,attr"`
+	Width  string `xml:"width,attr"`
+	Hidden string `xml:"hidden,attr"`
+}
+
+// mergeCell holds the range of a merged
+// cell, such as "A1:F1".
+type mergeCell struct {
+	Ref string `xml:"ref,attr"`
 }
 
 // sheetData represents the sheet data of
@@ -45,6 +139,7 @@ type row struct {
 	Ht           string `xml:"ht,attr"`
 	ThickBot     string `xml:"thickBot,attr"`
 	CustomHeight string `xml:"customHeight,attr"`
+	Hidden       string `xml:"hidden,attr"`
 	Cells        []cell `xml:"c"`
 }
 
@@ -56,6 +151,8 @@ type cell struct {
 	Type       string `xml:"t,attr"`
 	Value      string `xml:"v"`
 	F          string `xml:"f"`
+
+	InlineString stringItem `xml:"is"`
 }
 
 // SharedStrings represents a shared strings
@@ -70,7 +167,8 @@ type SharedStrings struct {
 }
 
 // stringItem contains the shared string
-// text element
+// text element. It is also used for the
+// inline strings of cells.
 type stringItem struct {
 	Text         ssText         `xml:"t"`
 	RichTextRuns []richTextRuns `xml:"r"`
@@ -0,0 +1,61 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// testSheet is a work sheet of a
+// workbook made by workbookFiles.
+type testSheet struct {
+	name  string
+	state string
+	xml   string
+}
+
+// zipFiles zips the given files into
+// the data of an XLSX document.
+func zipFiles(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("could not create zip file %v: %v", name, err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatalf("could not write zip file %v: %v", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("could not close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// workbookFiles zips a workbook with the given
+// work sheets, in order, into the data of an
+// XLSX document.
+func workbookFiles(t *testing.T, sheets ...testSheet) []byte {
+	var workbook, rels strings.Builder
+	files := map[string]string{
+		"[Content_Types].xml": `<Types><Override PartName="/xl/workbook.xml" ContentType="` + fileTypeWorkbook + `"/></Types>`,
+	}
+	for i, sheet := range sheets {
+		state := ""
+		if sheet.state != "" {
+			state = fmt.Sprintf(` state="%v"`, sheet.state)
+		}
+		fmt.Fprintf(&workbook, "<sheet name=%q sheetId=\"%v\"%v r:id=\"rId%v\"/>\n", sheet.name, i+1, state, i+1)
+		fmt.Fprintf(&rels, "<Relationship Id=\"rId%v\" Type=%q Target=\"worksheets/sheet%v.xml\"/>\n", i+1, relationshipTypeWorkSheet, i+1)
+		files[fmt.Sprintf("xl/worksheets/sheet%v.xml", i+1)] = sheet.xml
+	}
+	files["xl/workbook.xml"] = `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>
+` + workbook.String() + `</sheets></workbook>`
+	files["xl/_rels/workbook.xml.rels"] = `<Relationships>
+` + rels.String() + `</Relationships>`
+	return zipFiles(t, files)
+}
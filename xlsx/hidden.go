@@ -0,0 +1,201 @@
+package xlsx
+
+import (
+	"fmt"
+	"github.com/kinluek/texttable"
+	"strconv"
+)
+
+// HiddenMode sets how hidden rows, columns
+// and sheets are extracted.
+type HiddenMode int
+
+const (
+	// HiddenInclude extracts hidden content
+	// as if it were visible.
+	HiddenInclude HiddenMode = iota
+	// HiddenExclude leaves hidden content out.
+	HiddenExclude
+	// HiddenMark extracts hidden content, writing the hidden
+	// marker before the text of every hidden cell, and before
+	// the title of hidden sheets.
+	HiddenMark
+)
+
+// defaultHiddenMarker is the default marker
+// written before hidden content.
+const defaultHiddenMarker = "(hidden)"
+
+// isSet reports whether a boolean attribute is true.
+func isSet(attr string) bool {
+	return attr == "1" || attr == "true"
+}
+
+// hiddenRows returns the indexes of the
+// hidden rows of a work sheet.
+func hiddenRows(sheet WorkSheet) (map[int]bool, error) {
+	rowYs, _, err := cellPositions(sheet.SheetData.Rows)
+	if err != nil {
+		return nil, err
+	}
+	hidden := make(map[int]bool)
+	for i, row := range sheet.SheetData.Rows {
+		if isSet(row.Hidden) {
+			hidden[rowYs[i]] = true
+		}
+	}
+	return hidden, nil
+}
+
+// columnRange is a range of column indexes,
+// from first to last inclusive.
+type columnRange struct {
+	first, last int
+}
+
+// columnRanges holds ranges of column indexes.
+type columnRanges []columnRange
+
+// contains reports whether column x is in one of the ranges.
+func (ranges columnRanges) contains(x int) bool {
+	for _, r := range ranges {
+		if r.first <= x && x <= r.last {
+			return true
+		}
+	}
+	return false
+}
+
+// parseColRange returns the range of column indexes of a
+// column definition, which is cut to the columns a work
+// sheet can have.
+func parseColRange(c col) (columnRange, error) {
+	first, err := strconv.Atoi(c.Min)
+	if err != nil {
+		return columnRange{}, fmt.Errorf("could not parse column index %v: %v", c.Min, err)
+	}
+	last, err := strconv.Atoi(c.Max)
+	if err != nil {
+		return columnRange{}, fmt.Errorf("could not parse column index %v: %v", c.Max, err)
+	}
+	if first > last {
+		return columnRange{}, fmt.Errorf("invalid column range %v:%v", c.Min, c.Max)
+	}
+	if first < 1 {
+		first = 1
+	}
+	if last > maxColumns {
+		last = maxColumns
+	}
+	return columnRange{first: first - 1, last: last - 1}, nil
+}
+
+// hiddenColumns returns the ranges of the
+// hidden columns of a work sheet.
+func hiddenColumns(sheet WorkSheet) (columnRanges, error) {
+	var hidden columnRanges
+	for _, c := range sheet.Cols {
+		if !isSet(c.Hidden) {
+			continue
+		}
+		r, err := parseColRange(c)
+		if err != nil {
+			return nil, err
+		}
+		if r.first <= r.last {
+			hidden = append(hidden, r)
+		}
+	}
+	return hidden, nil
+}
+
+// applyHidden applies the hidden rows and columns of the work
+// sheet to its text matrix and the spans of its merged cells,
+// as set by the mode. Excluding rows and columns moves the
+// spans with the cells, and shortens them by the hidden
+// columns they cover.
+func applyHidden(textMatrix [][]string, spans map[texttable.Cell]int, sheet WorkSheet, mode HiddenMode, marker string) ([][]string, map[texttable.Cell]int, error) {
+	if mode == HiddenInclude {
+		return textMatrix, spans, nil
+	}
+	rows, err := hiddenRows(sheet)
+	if err != nil {
+		return nil, nil, err
+	}
+	columns, err := hiddenColumns(sheet)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	visible := make([][]string, 0, len(textMatrix))
+	for y, row := range textMatrix {
+		if row, ok := hideRow(row, rows[y], columns, mode, marker); ok {
+			visible = append(visible, row)
+		}
+	}
+	if mode != HiddenExclude || spans == nil {
+		return visible, spans, nil
+	}
+
+	// newX maps the index of each visible
+	// column to its index once excluded.
+	newX := make(map[int]int)
+	if len(textMatrix) > 0 {
+		for x := range textMatrix[0] {
+			if !columns.contains(x) {
+				newX[x] = len(newX)
+			}
+		}
+	}
+
+	kept := make(map[texttable.Cell]int)
+	newY := 0
+	for y := range textMatrix {
+		if rows[y] {
+			continue
+		}
+		for x := range textMatrix[y] {
+			span, ok := spans[texttable.Cell{Row: y, Column: x}]
+			if !ok || columns.contains(x) {
+				continue
+			}
+			n := 0
+			for i := x; i < x+span; i++ {
+				if !columns.contains(i) {
+					n++
+				}
+			}
+			if n > 1 {
+				kept[texttable.Cell{Row: newY, Column: newX[x]}] = n
+			}
+		}
+		newY++
+	}
+	return visible, kept, nil
+}
+
+// hideRow applies the mode to a row, which is itself hidden
+// if hiddenRow is set, and to the cells of its hidden columns.
+// It reports whether the row is kept.
+func hideRow(row []string, hiddenRow bool, columns columnRanges, mode HiddenMode, marker string) ([]string, bool) {
+	switch mode {
+	case HiddenMark:
+		for x, text := range row {
+			if text != "" && (hiddenRow || columns.contains(x)) {
+				row[x] = marker + " " + text
+			}
+		}
+	case HiddenExclude:
+		if hiddenRow {
+			return nil, false
+		}
+		kept := make([]string, 0, len(row))
+		for x, text := range row {
+			if !columns.contains(x) {
+				kept = append(kept, text)
+			}
+		}
+		return kept, true
+	}
+	return row, true
+}
@@ -0,0 +1,153 @@
+package xlsx
+
+import (
+	"encoding/xml"
+	"github.com/kinluek/texttable"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestApplyHidden(t *testing.T) {
+	sheetXML := `<worksheet>
+<cols><col min="2" max="2" hidden="1"/></cols>
+<sheetData>
+<row r="1"><c r="A1"><v>1</v></c><c r="B1"><v>2</v></c><c r="C1"><v>3</v></c><c r="D1"><v>4</v></c></row>
+<row r="2" hidden="1"><c r="A2"><v>5</v></c><c r="B2"><v>6</v></c></row>
+<row r="3"><c r="A3"><v>7</v></c><c r="D3"><v>8</v></c></row>
+</sheetData></worksheet>`
+
+	var sheet WorkSheet
+	if err := xml.Unmarshal([]byte(sheetXML), &sheet); err != nil {
+		t.Fatalf("could not decode work sheet: %v", err)
+	}
+	spans := map[texttable.Cell]int{{Row: 2, Column: 0}: 3}
+
+	tests := []struct {
+		name      string
+		mode      HiddenMode
+		wantText  [][]string
+		wantSpans map[texttable.Cell]int
+	}{
+		{
+			name: "include",
+			mode: HiddenInclude,
+			wantText: [][]string{
+				{"1", "2", "3", "4"},
+				{"5", "6", "", ""},
+				{"7", "", "", "8"},
+			},
+			wantSpans: map[texttable.Cell]int{{Row: 2, Column: 0}: 3},
+		},
+		{
+			name: "exclude",
+			mode: HiddenExclude,
+			wantText: [][]string{
+				{"1", "3", "4"},
+				{"7", "", "8"},
+			},
+			wantSpans: map[texttable.Cell]int{{Row: 1, Column: 0}: 2},
+		},
+		{
+			name: "mark",
+			mode: HiddenMark,
+			wantText: [][]string{
+				{"1", "(x) 2", "3", "4"},
+				{"(x) 5", "(x) 6", "", ""},
+				{"7", "", "", "8"},
+			},
+			wantSpans: map[texttable.Cell]int{{Row: 2, Column: 0}: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			textMatrix, err := makeTextMatrix(sheet, newCellReader(nil, Config{}))
+			if err != nil {
+				t.Fatalf("could not make text matrix: %v", err)
+			}
+			gotText, gotSpans, err := applyHidden(textMatrix, spans, sheet, tt.mode, "(x)")
+			if err != nil {
+				t.Fatalf("could not apply hidden: %v", err)
+			}
+			if !reflect.DeepEqual(gotText, tt.wantText) {
+				t.Fatalf("expected: \n%q\n\ngot: \n%q\n", tt.wantText, gotText)
+			}
+			if !reflect.DeepEqual(gotSpans, tt.wantSpans) {
+				t.Fatalf("expected: \n%v\n\ngot: \n%v\n", tt.wantSpans, gotSpans)
+			}
+		})
+	}
+}
+
+func TestExtract_HiddenSheets(t *testing.T) {
+	sheetXML := `<worksheet><sheetData><row r="1"><c r="A1"><v>1</v></c></row></sheetData></worksheet>`
+	data := workbookFiles(t,
+		testSheet{name: "Shown", xml: sheetXML},
+		testSheet{name: "Scratch", state: "hidden", xml: sheetXML},
+	)
+
+	tests := []struct {
+		name string
+		mode HiddenMode
+		want []string
+	}{
+		{"include", HiddenInclude, []string{"Shown", "Scratch"}},
+		{"exclude", HiddenExclude, []string{"Shown"}},
+		{"mark", HiddenMark, []string{"Shown", "(hidden) Scratch"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extracts, err := ExtractBytes(data, Config{ColumnWidth: 20, SheetNameTitle: true, Hidden: tt.mode})
+			if err != nil {
+				t.Fatalf("could not extract: %v", err)
+			}
+			var got []string
+			for _, extract := range extracts {
+				title := strings.SplitN(extract.Text, "\n", 2)[0]
+				got = append(got, strings.TrimSpace(title))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected: \n%q\n\ngot: \n%q\n", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestHiddenColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		cols    []col
+		want    columnRanges
+		wantErr bool
+	}{
+		{
+			name: "ranges",
+			cols: []col{{Min: "2", Max: "3", Hidden: "1"}, {Min: "5", Max: "5"}, {Min: "7", Max: "7", Hidden: "true"}},
+			want: columnRanges{{first: 1, last: 2}, {first: 6, last: 6}},
+		},
+		{
+			name: "cut to the sheet",
+			cols: []col{{Min: "-5", Max: "2147483648", Hidden: "1"}},
+			want: columnRanges{{first: 0, last: maxColumns - 1}},
+		},
+		{
+			name:    "min after max",
+			cols:    []col{{Min: "3", Max: "2", Hidden: "1"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := hiddenColumns(WorkSheet{Cols: tt.cols})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error: %v, got: %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected: \n%v\n\ngot: \n%v\n", tt.want, got)
+			}
+		})
+	}
+}
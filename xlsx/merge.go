@@ -0,0 +1,89 @@
+package xlsx
+
+import (
+	"fmt"
+	"github.com/kinluek/texttable"
+	"strings"
+)
+
+// MergeMode sets how merged cells are extracted.
+type MergeMode int
+
+const (
+	// MergeIgnore writes the value of a merged cell in its
+	// top left cell only, leaving the cells it covers empty.
+	MergeIgnore MergeMode = iota
+	// MergeFill copies the value of a merged cell into
+	// every cell it covers.
+	MergeFill
+	// MergeSpan wraps the value of a merged cell across the
+	// width of the columns it covers.
+	MergeSpan
+)
+
+// mergeRange is the range of a merged cell,
+// as indexes into the text matrix.
+type mergeRange struct {
+	x1, y1, x2, y2 int
+}
+
+// parseMergeRange parses a merged cell range, such as "A1:F1".
+// A range of a single cell has no colon.
+func parseMergeRange(ref string) (mergeRange, error) {
+	from, to := ref, ref
+	if i := strings.Index(ref, ":"); i >= 0 {
+		from, to = ref[:i], ref[i+1:]
+	}
+	x1, y1, err := parseXYCoordinate(from)
+	if err != nil {
+		return mergeRange{}, fmt.Errorf("invalid merge range %v: %v", ref, err)
+	}
+	x2, y2, err := parseXYCoordinate(to)
+	if err != nil {
+		return mergeRange{}, fmt.Errorf("invalid merge range %v: %v", ref, err)
+	}
+	if x2 < x1 {
+		x1, x2 = x2, x1
+	}
+	if y2 < y1 {
+		y1, y2 = y2, y1
+	}
+	return mergeRange{x1: x1, y1: y1, x2: x2, y2: y2}, nil
+}
+
+// applyMerges applies the merged cells of the work sheet to
+// its text matrix, as set by the mode. It returns the column
+// spans of the merged cells when the mode is MergeSpan.
+// Ranges reaching past the text matrix are cut short.
+func applyMerges(textMatrix [][]string, sheet WorkSheet, mode MergeMode) (map[texttable.Cell]int, error) {
+	if mode == MergeIgnore || len(sheet.MergeCells) == 0 {
+		return nil, nil
+	}
+	var spans map[texttable.Cell]int
+	if mode == MergeSpan {
+		spans = make(map[texttable.Cell]int)
+	}
+	for _, merged := range sheet.MergeCells {
+		r, err := parseMergeRange(merged.Ref)
+		if err != nil {
+			return nil, err
+		}
+		if r.y1 >= len(textMatrix) || r.x1 >= len(textMatrix[r.y1]) {
+			continue
+		}
+		value := textMatrix[r.y1][r.x1]
+		for y := r.y1; y <= r.y2 && y < len(textMatrix); y++ {
+			switch mode {
+			case MergeFill:
+				for x := r.x1; x <= r.x2 && x < len(textMatrix[y]); x++ {
+					textMatrix[y][x] = value
+				}
+			case MergeSpan:
+				if r.x2 > r.x1 {
+					spans[texttable.Cell{Row: y, Column: r.x1}] = r.x2 - r.x1 + 1
+				}
+			}
+		}
+	}
+	return spans, nil
+}
@@ -0,0 +1,82 @@
+package xlsx
+
+import (
+	"encoding/xml"
+	"github.com/kinluek/texttable"
+	"reflect"
+	"testing"
+)
+
+func TestApplyMerges(t *testing.T) {
+	sheetXML := `<worksheet><sheetData>
+<row r="1"><c r="A1" t="inlineStr"><is><t>title</t></is></c></row>
+<row r="2"><c r="A2"><v>1</v></c><c r="C2"><v>2</v></c></row>
+<row r="3"><c r="A3"><v>3</v></c><c r="C3"><v>4</v></c></row>
+</sheetData>
+<mergeCells count="2"><mergeCell ref="A1:C1"/><mergeCell ref="A2:B3"/></mergeCells>
+</worksheet>`
+
+	var sheet WorkSheet
+	if err := xml.Unmarshal([]byte(sheetXML), &sheet); err != nil {
+		t.Fatalf("could not decode work sheet: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		mode      MergeMode
+		wantText  [][]string
+		wantSpans map[texttable.Cell]int
+	}{
+		{
+			name: "ignore",
+			mode: MergeIgnore,
+			wantText: [][]string{
+				{"title", "", ""},
+				{"1", "", "2"},
+				{"3", "", "4"},
+			},
+		},
+		{
+			name: "fill",
+			mode: MergeFill,
+			wantText: [][]string{
+				{"title", "title", "title"},
+				{"1", "1", "2"},
+				{"1", "1", "4"},
+			},
+		},
+		{
+			name: "span",
+			mode: MergeSpan,
+			wantText: [][]string{
+				{"title", "", ""},
+				{"1", "", "2"},
+				{"3", "", "4"},
+			},
+			wantSpans: map[texttable.Cell]int{
+				{Row: 0, Column: 0}: 3,
+				{Row: 1, Column: 0}: 2,
+				{Row: 2, Column: 0}: 2,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			textMatrix, err := makeTextMatrix(sheet, newCellReader(nil, Config{}))
+			if err != nil {
+				t.Fatalf("could not make text matrix: %v", err)
+			}
+			spans, err := applyMerges(textMatrix, sheet, tt.mode)
+			if err != nil {
+				t.Fatalf("could not apply merges: %v", err)
+			}
+			if !reflect.DeepEqual(textMatrix, tt.wantText) {
+				t.Fatalf("expected: \n%q\n\ngot: \n%q\n", tt.wantText, textMatrix)
+			}
+			if !reflect.DeepEqual(spans, tt.wantSpans) {
+				t.Fatalf("expected: \n%v\n\ngot: \n%v\n", tt.wantSpans, spans)
+			}
+		})
+	}
+}
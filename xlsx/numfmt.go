@@ -0,0 +1,964 @@
+package xlsx
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// builtInNumFmts are the format codes of the built-in number
+// formats, which styles refer to by ID without defining them.
+// The date formats are those Excel shows in the United States.
+var builtInNumFmts = map[int]string{
+	0:  "General",
+	1:  "0",
+	2:  "0.00",
+	3:  "#,##0",
+	4:  "#,##0.00",
+	5:  `"$"#,##0_);\("$"#,##0\)`,
+	6:  `"$"#,##0_);[Red]\("$"#,##0\)`,
+	7:  `"$"#,##0.00_);\("$"#,##0.00\)`,
+	8:  `"$"#,##0.00_);[Red]\("$"#,##0.00\)`,
+	9:  "0%",
+	10: "0.00%",
+	11: "0.00E+00",
+	12: "# ?/?",
+	13: "# ??/??",
+	14: "m/d/yyyy",
+	15: "d-mmm-yy",
+	16: "d-mmm",
+	17: "mmm-yy",
+	18: "h:mm AM/PM",
+	19: "h:mm:ss AM/PM",
+	20: "h:mm",
+	21: "h:mm:ss",
+	22: "m/d/yyyy h:mm",
+	37: "#,##0 ;(#,##0)",
+	38: "#,##0 ;[Red](#,##0)",
+	39: "#,##0.00;(#,##0.00)",
+	40: "#,##0.00;[Red](#,##0.00)",
+	41: `_(* #,##0_);_(* \(#,##0\);_(* "-"_);_(@_)`,
+	42: `_("$"* #,##0_);_("$"* \(#,##0\);_("$"* "-"_);_(@_)`,
+	43: `_(* #,##0.00_);_(* \(#,##0.00\);_(* "-"??_);_(@_)`,
+	44: `_("$"* #,##0.00_);_("$"* \(#,##0.00\);_("$"* "-"??_);_(@_)`,
+	45: "mm:ss",
+	46: "[h]:mm:ss",
+	47: "mm:ss.0",
+	48: "##0.0E+0",
+	49: "@",
+}
+
+// tokenKind is the kind of a token of a number format section.
+type tokenKind int
+
+const (
+	tokenLiteral   tokenKind = iota // text output as it is
+	tokenDigit                      // a digit placeholder: 0, # or ?
+	tokenDecimal                    // the decimal point
+	tokenExponent                   // E+ or E-
+	tokenSlash                      // the slash of a fraction
+	tokenText                       // @, the text of the cell
+	tokenGeneral                    // General
+	tokenDate                       // a date or time part, such as yyyy or ss
+	tokenElapsed                    // elapsed time, such as [h]
+	tokenSubsecond                  // fractions of a second, such as .00
+	tokenAmPm                       // AM/PM or A/P
+)
+
+// digit parts say which part of the
+// number a digit placeholder is for.
+const (
+	partInteger = iota
+	partFraction
+	partExponent
+	partWhole
+	partNumerator
+	partDenominator
+)
+
+// formatToken is a token of a number format section.
+type formatToken struct {
+	kind tokenKind
+	text string
+	part int
+}
+
+// formatSection is one of the up to four sections of
+// a number format, which are separated by ';'.
+type formatSection struct {
+	tokens []formatToken
+
+	hasCondition   bool
+	conditionOp    string
+	conditionValue float64
+
+	isDate   bool
+	isNumber bool
+	hasText  bool
+	hasAmPm  bool
+
+	grouping    bool
+	scale       int
+	percent     int
+	decimals    int
+	exponent    bool
+	fraction    bool
+	denominator int
+	subseconds  int
+}
+
+// numberFormat is a parsed number format code.
+type numberFormat struct {
+	sections []*formatSection
+}
+
+// parseNumFmt parses a number format code.
+func parseNumFmt(code string) *numberFormat {
+	nf := &numberFormat{}
+	for _, section := range splitSections(code) {
+		nf.sections = append(nf.sections, parseSection(section))
+	}
+	return nf
+}
+
+// splitSections splits a format code at the semicolons
+// which are not quoted, escaped or inside brackets.
+func splitSections(code string) []string {
+	var sections []string
+	var b strings.Builder
+	runes := []rune(code)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch r {
+		case '"':
+			j := i + 1
+			for j < len(runes) && runes[j] != '"' {
+				j++
+			}
+			if j == len(runes) {
+				j--
+			}
+			b.WriteString(string(runes[i : j+1]))
+			i = j
+			continue
+		case '\\', '_', '*':
+			if i+1 < len(runes) {
+				b.WriteRune(r)
+				b.WriteRune(runes[i+1])
+				i++
+				continue
+			}
+		case '[':
+			j := i + 1
+			for j < len(runes) && runes[j] != ']' {
+				j++
+			}
+			if j == len(runes) {
+				j--
+			}
+			b.WriteString(string(runes[i : j+1]))
+			i = j
+			continue
+		case ';':
+			sections = append(sections, b.String())
+			b.Reset()
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return append(sections, b.String())
+}
+
+// parseSection parses a section of a number format code.
+func parseSection(code string) *formatSection {
+	fs := &formatSection{}
+	runes := []rune(code)
+	isPlaceholder := func(i int) bool {
+		return i < len(runes) && (runes[i] == '0' || runes[i] == '#' || runes[i] == '?')
+	}
+	lastKind := func() tokenKind {
+		for i := len(fs.tokens) - 1; i >= 0; i-- {
+			if fs.tokens[i].kind != tokenLiteral {
+				return fs.tokens[i].kind
+			}
+		}
+		return tokenLiteral
+	}
+	literal := func(text string) {
+		fs.tokens = append(fs.tokens, formatToken{kind: tokenLiteral, text: text})
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		lower := unicode.ToLower(r)
+		switch {
+		case r == '"':
+			j := i + 1
+			for j < len(runes) && runes[j] != '"' {
+				j++
+			}
+			literal(string(runes[i+1 : j]))
+			i = j
+		case r == '\\' && i+1 < len(runes):
+			literal(string(runes[i+1]))
+			i++
+		case r == '_' && i+1 < len(runes):
+			// space the width of the next character
+			literal(" ")
+			i++
+		case r == '*' && i+1 < len(runes):
+			// the next character fills the cell,
+			// which has no width to fill here
+			i++
+		case r == '[':
+			j := i + 1
+			for j < len(runes) && runes[j] != ']' {
+				j++
+			}
+			fs.parseBracket(string(runes[i+1 : min(j, len(runes))]))
+			i = j
+		case isPlaceholder(i):
+			fs.tokens = append(fs.tokens, formatToken{kind: tokenDigit, text: string(r)})
+		case r == '.':
+			kind := lastKind()
+			if (kind == tokenDate || kind == tokenElapsed) && i+1 < len(runes) && runes[i+1] == '0' {
+				j := i + 1
+				for j < len(runes) && runes[j] == '0' {
+					j++
+				}
+				fs.subseconds = j - i - 1
+				fs.tokens = append(fs.tokens, formatToken{kind: tokenSubsecond})
+				i = j - 1
+				continue
+			}
+			fs.tokens = append(fs.tokens, formatToken{kind: tokenDecimal})
+		case r == ',' && lastKind() == tokenDigit:
+			if isPlaceholder(i + 1) {
+				fs.grouping = true
+			} else {
+				fs.scale++
+			}
+		case r == '%':
+			fs.percent++
+			literal("%")
+		case lower == 'e' && i+1 < len(runes) && (runes[i+1] == '+' || runes[i+1] == '-') && lastKind() == tokenDigit:
+			fs.tokens = append(fs.tokens, formatToken{kind: tokenExponent, text: string(runes[i+1])})
+			fs.exponent = true
+			i++
+		case r == '/' && lastKind() == tokenDigit && i+1 < len(runes) && (isPlaceholder(i+1) || unicode.IsDigit(runes[i+1])):
+			fs.tokens = append(fs.tokens, formatToken{kind: tokenSlash})
+			fs.fraction = true
+			j := i + 1
+			for j < len(runes) && '0' <= runes[j] && runes[j] <= '9' {
+				j++
+			}
+			if j > i+1 && !isPlaceholder(j) {
+				fs.denominator, _ = strconv.Atoi(string(runes[i+1 : j]))
+				i = j - 1
+			}
+		case r == '@':
+			fs.tokens = append(fs.tokens, formatToken{kind: tokenText})
+			fs.hasText = true
+		case strings.HasPrefix(strings.ToLower(string(runes[i:])), "general"):
+			fs.tokens = append(fs.tokens, formatToken{kind: tokenGeneral})
+			fs.isNumber = true
+			i += len("general") - 1
+		case strings.HasPrefix(strings.ToLower(string(runes[i:])), "am/pm"):
+			fs.tokens = append(fs.tokens, formatToken{kind: tokenAmPm, text: string(runes[i : i+5])})
+			fs.hasAmPm = true
+			i += 4
+		case strings.HasPrefix(strings.ToLower(string(runes[i:])), "a/p"):
+			fs.tokens = append(fs.tokens, formatToken{kind: tokenAmPm, text: string(runes[i : i+3])})
+			fs.hasAmPm = true
+			i += 2
+		case lower == 'y' || lower == 'm' || lower == 'd' || lower == 'h' || lower == 's':
+			j := i
+			for j < len(runes) && unicode.ToLower(runes[j]) == lower {
+				j++
+			}
+			fs.tokens = append(fs.tokens, formatToken{kind: tokenDate, text: strings.Repeat(string(lower), j-i)})
+			i = j - 1
+		default:
+			literal(string(r))
+		}
+	}
+	fs.resolve()
+	return fs
+}
+
+// parseBracket parses the text between the brackets of a
+// section: a currency, elapsed time, condition or a color,
+// which is ignored.
+func (fs *formatSection) parseBracket(text string) {
+	lower := strings.ToLower(text)
+	switch {
+	case strings.HasPrefix(text, "$"):
+		// currency and locale, such as [$€-407]
+		currency := text[1:]
+		if i := strings.Index(currency, "-"); i >= 0 {
+			currency = currency[:i]
+		}
+		if currency != "" {
+			fs.tokens = append(fs.tokens, formatToken{kind: tokenLiteral, text: currency})
+		}
+	case lower != "" && strings.Trim(lower, "hms") == "":
+		fs.tokens = append(fs.tokens, formatToken{kind: tokenElapsed, text: lower})
+	case strings.HasPrefix(text, "<") || strings.HasPrefix(text, ">") || strings.HasPrefix(text, "="):
+		op := text[:1]
+		if len(text) > 1 && (text[1] == '=' || text[1] == '>') {
+			op = text[:2]
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(text[len(op):]), 64)
+		if err == nil {
+			fs.hasCondition = true
+			fs.conditionOp = op
+			fs.conditionValue = value
+		}
+	}
+}
+
+// resolve works out what kind of section it is, which part
+// of the number each digit placeholder is for, and whether
+// each m is for months or minutes.
+func (fs *formatSection) resolve() {
+	slash := -1
+	for i, token := range fs.tokens {
+		switch token.kind {
+		case tokenDate, tokenElapsed, tokenAmPm:
+			fs.isDate = true
+		case tokenDigit:
+			fs.isNumber = true
+		case tokenSlash:
+			slash = i
+		}
+	}
+	if fs.isDate {
+		fs.resolveMinutes()
+		return
+	}
+
+	part := partInteger
+	for i := range fs.tokens {
+		switch fs.tokens[i].kind {
+		case tokenDecimal:
+			part = partFraction
+		case tokenExponent:
+			part = partExponent
+		case tokenDigit:
+			fs.tokens[i].part = part
+			if part == partFraction {
+				fs.decimals++
+			}
+		}
+	}
+	if slash < 0 {
+		return
+	}
+
+	// the placeholders just before the slash are the numerator,
+	// any before those are the whole number, and those after
+	// the slash are the denominator.
+	i := slash - 1
+	for ; i >= 0 && fs.tokens[i].kind == tokenDigit; i-- {
+		fs.tokens[i].part = partNumerator
+	}
+	for ; i >= 0; i-- {
+		if fs.tokens[i].kind == tokenDigit {
+			fs.tokens[i].part = partWhole
+		}
+	}
+	for i := slash + 1; i < len(fs.tokens); i++ {
+		if fs.tokens[i].kind == tokenDigit {
+			fs.tokens[i].part = partDenominator
+		}
+	}
+	fs.decimals = 0
+}
+
+// resolveMinutes marks the m and mm date tokens which follow
+// hours, or come before seconds, as minutes.
+func (fs *formatSection) resolveMinutes() {
+	previous := ""
+	for i, token := range fs.tokens {
+		if token.kind != tokenDate && token.kind != tokenElapsed {
+			continue
+		}
+		if token.kind == tokenDate && strings.HasPrefix(token.text, "m") && len(token.text) <= 2 {
+			next := ""
+			for _, t := range fs.tokens[i+1:] {
+				if t.kind == tokenDate || t.kind == tokenElapsed {
+					next = t.text
+					break
+				}
+			}
+			if strings.HasPrefix(previous, "h") || strings.HasPrefix(next, "s") {
+				fs.tokens[i].text = strings.Replace(token.text, "m", "n", -1)
+			}
+		}
+		previous = token.text
+	}
+}
+
+// formatNumber formats a number with the number format.
+func (nf *numberFormat) formatNumber(value float64, date1904 bool) (string, bool) {
+	sections := nf.sections
+	// a fourth section, or a lone text section,
+	// is only used for text.
+	if len(sections) > 3 {
+		sections = sections[:3]
+	}
+	if len(sections) == 1 && sections[0].hasText && !sections[0].isNumber {
+		return "", false
+	}
+
+	section, negate := sections[0], value < 0
+	switch {
+	case sections[0].hasCondition:
+		section, negate = nf.conditionalSection(sections, value)
+	case len(sections) >= 2 && value < 0:
+		section, negate = sections[1], false
+		value = -value
+	case len(sections) >= 3 && value == 0:
+		section = sections[2]
+	}
+	if section == nil {
+		return "", false
+	}
+
+	switch {
+	case section.isDate:
+		if value < 0 {
+			return "", false
+		}
+		return section.formatDate(value, date1904), true
+	case negate:
+		return "-" + section.formatNumber(-value), true
+	}
+	return section.formatNumber(value), true
+}
+
+// conditionalSection returns the section for a value when the
+// sections have conditions, and whether the value should be
+// shown with a minus sign.
+func (nf *numberFormat) conditionalSection(sections []*formatSection, value float64) (*formatSection, bool) {
+	for i, section := range sections {
+		if !section.hasCondition {
+			return section, value < 0 && i == 0
+		}
+		if section.matches(value) {
+			if value < 0 && i > 0 {
+				return section, false
+			}
+			return section, value < 0
+		}
+	}
+	return nil, false
+}
+
+// matches reports whether a value meets the condition of the section.
+func (fs *formatSection) matches(value float64) bool {
+	switch fs.conditionOp {
+	case "<":
+		return value < fs.conditionValue
+	case "<=":
+		return value <= fs.conditionValue
+	case ">":
+		return value > fs.conditionValue
+	case ">=":
+		return value >= fs.conditionValue
+	case "=":
+		return value == fs.conditionValue
+	case "<>":
+		return value != fs.conditionValue
+	}
+	return false
+}
+
+// formatText formats text with the text section of the
+// number format. ok is false if it has no text section.
+func (nf *numberFormat) formatText(text string) (string, bool) {
+	var section *formatSection
+	switch {
+	case len(nf.sections) > 3:
+		section = nf.sections[3]
+	case len(nf.sections) == 1 && nf.sections[0].hasText:
+		section = nf.sections[0]
+	default:
+		return "", false
+	}
+	var b strings.Builder
+	for _, token := range section.tokens {
+		switch token.kind {
+		case tokenLiteral:
+			b.WriteString(token.text)
+		case tokenText:
+			b.WriteString(text)
+		}
+	}
+	return b.String(), true
+}
+
+// formatNumber formats a positive number with the section.
+func (fs *formatSection) formatNumber(value float64) string {
+	for i := 0; i < fs.percent; i++ {
+		value *= 100
+	}
+	for i := 0; i < fs.scale; i++ {
+		value /= 1000
+	}
+
+	digits := make(map[int][]string)
+	placeholders := make(map[int][]string)
+	for _, token := range fs.tokens {
+		if token.kind == tokenDigit {
+			placeholders[token.part] = append(placeholders[token.part], token.text)
+		}
+	}
+
+	var exponent int
+	blankFraction := false
+	switch {
+	case fs.exponent:
+		exponent, value = splitExponent(value, placeholders[partInteger], fs.decimals)
+		expDigits := strconv.Itoa(abs(exponent))
+		digits[partExponent] = fillInteger(expDigits, placeholders[partExponent])
+		fallthrough
+	case !fs.fraction:
+		s := strconv.FormatFloat(value, 'f', fs.decimals, 64)
+		intDigits, fracDigits := s, ""
+		if i := strings.Index(s, "."); i >= 0 {
+			intDigits, fracDigits = s[:i], s[i+1:]
+		}
+		if intDigits == "0" {
+			intDigits = ""
+		}
+		digits[partInteger] = fillInteger(intDigits, placeholders[partInteger])
+		digits[partFraction] = fillFraction(fracDigits, placeholders[partFraction])
+		if fs.grouping {
+			digits[partInteger] = groupInteger(digits[partInteger])
+		}
+	default:
+		whole, numerator, denominator := splitFraction(value, len(placeholders[partWhole]) > 0,
+			fs.denominator, len(placeholders[partDenominator]))
+		wholeDigits := ""
+		if whole > 0 {
+			wholeDigits = strconv.Itoa(whole)
+		}
+		digits[partWhole] = fillInteger(wholeDigits, placeholders[partWhole])
+		if fs.grouping {
+			digits[partWhole] = groupInteger(digits[partWhole])
+		}
+		if numerator == 0 && len(placeholders[partWhole]) > 0 && whole > 0 {
+			blankFraction = true
+		}
+		digits[partNumerator] = fillInteger(strconv.Itoa(numerator), placeholders[partNumerator])
+		if fs.denominator == 0 {
+			digits[partDenominator] = fillFraction(strconv.Itoa(denominator), placeholders[partDenominator])
+		}
+	}
+
+	var b strings.Builder
+	next := make(map[int]int)
+	for _, token := range fs.tokens {
+		switch token.kind {
+		case tokenLiteral:
+			b.WriteString(token.text)
+		case tokenDigit:
+			d := digits[token.part][next[token.part]]
+			next[token.part]++
+			if blankFraction && (token.part == partNumerator || token.part == partDenominator) {
+				d = strings.Repeat(" ", len(d))
+			}
+			b.WriteString(d)
+		case tokenDecimal:
+			b.WriteString(".")
+		case tokenExponent:
+			b.WriteString("E")
+			if exponent < 0 {
+				b.WriteString("-")
+			} else if token.text == "+" {
+				b.WriteString("+")
+			}
+		case tokenSlash:
+			if blankFraction {
+				b.WriteString(" ")
+			} else {
+				b.WriteString("/")
+			}
+			if fs.denominator > 0 && !blankFraction {
+				b.WriteString(strconv.Itoa(fs.denominator))
+			} else if fs.denominator > 0 {
+				b.WriteString(strings.Repeat(" ", len(strconv.Itoa(fs.denominator))))
+			}
+		case tokenGeneral:
+			b.WriteString(formatGeneral(value))
+		case tokenText:
+			b.WriteString(formatGeneral(value))
+		}
+	}
+	return b.String()
+}
+
+// fillInteger places the digits of an integer in its placeholders,
+// from the right. Placeholders without a digit show a 0 for '0', a
+// space for '?' and nothing for '#', and the first placeholder
+// takes any digits left over.
+func fillInteger(digits string, placeholders []string) []string {
+	filled := make([]string, len(placeholders))
+	for i := len(placeholders) - 1; i >= 0; i-- {
+		switch {
+		case i == 0 && digits != "":
+			filled[i] = digits
+			digits = ""
+		case digits != "":
+			filled[i] = digits[len(digits)-1:]
+			digits = digits[:len(digits)-1]
+		case placeholders[i] == "0":
+			filled[i] = "0"
+		case placeholders[i] == "?":
+			filled[i] = " "
+		}
+	}
+	return filled
+}
+
+// fillFraction places the digits of a fraction in its
+// placeholders, from the left. Trailing zeros are left out
+// for '#', and are shown as spaces for '?'.
+func fillFraction(digits string, placeholders []string) []string {
+	filled := make([]string, len(placeholders))
+	trailing := true
+	for i := len(placeholders) - 1; i >= 0; i-- {
+		d := "0"
+		if i < len(digits) {
+			d = digits[i : i+1]
+		}
+		if trailing && d == "0" && placeholders[i] != "0" {
+			if placeholders[i] == "?" {
+				filled[i] = " "
+			}
+			continue
+		}
+		trailing = false
+		filled[i] = d
+	}
+	return filled
+}
+
+// groupInteger adds thousands separators to the filled integer
+// placeholders, which are joined into the first of them.
+func groupInteger(filled []string) []string {
+	if len(filled) == 0 {
+		return filled
+	}
+	joined := strings.Join(filled, "")
+	padding := len(joined) - len(strings.TrimLeft(joined, " "))
+	digits := joined[padding:]
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	grouped := make([]string, len(filled))
+	grouped[0] = strings.Repeat(" ", padding) + b.String()
+	return grouped
+}
+
+// splitExponent splits a number into its exponent and mantissa.
+// When there are several integer placeholders led by '#', the
+// exponent is a multiple of their count, as in engineering
+// notation. The exponent is moved on when rounding the mantissa
+// to the given number of decimals carries it into another
+// integer digit, as 9.999 is written 1.00E+01 rather than
+// 10.00E+00.
+func splitExponent(value float64, integer []string, decimals int) (int, float64) {
+	if value == 0 {
+		return 0, 0
+	}
+	exponent := scientificExponent(value, integer)
+	mantissa := value / math.Pow(10, float64(exponent))
+	rounded, err := strconv.ParseFloat(strconv.FormatFloat(mantissa, 'f', decimals, 64), 64)
+	if err == nil && rounded >= math.Pow(10, float64(max(len(integer), 1))) {
+		value = rounded * math.Pow(10, float64(exponent))
+		exponent = scientificExponent(value, integer)
+		mantissa = value / math.Pow(10, float64(exponent))
+	}
+	return exponent, mantissa
+}
+
+// scientificExponent returns the exponent of a number
+// written with the given integer placeholders.
+func scientificExponent(value float64, integer []string) int {
+	exponent := int(math.Floor(math.Log10(value)))
+	if n := len(integer); n > 1 && integer[0] == "#" {
+		exponent = int(math.Floor(float64(exponent)/float64(n))) * n
+	} else if n > 1 {
+		exponent -= n - 1
+	}
+	return exponent
+}
+
+// maxFractionDigits caps the number of digits of the
+// denominators chosen by splitFraction.
+const maxFractionDigits = 9
+
+// splitFraction splits a number into a whole number and a
+// fraction, with either the given fixed denominator, or the
+// denominator of at most the given number of digits which
+// comes closest to it.
+func splitFraction(value float64, hasWhole bool, fixed, digits int) (whole, numerator, denominator int) {
+	fraction := value
+	if hasWhole {
+		whole = int(math.Floor(value))
+		fraction = value - float64(whole)
+	}
+	if fixed > 0 {
+		denominator = fixed
+		numerator = int(math.Round(fraction * float64(fixed)))
+	} else {
+		digits = max(1, min(digits, maxFractionDigits))
+		numerator, denominator = closestFraction(fraction, int(math.Pow(10, float64(digits)))-1)
+	}
+	if hasWhole && numerator == denominator {
+		whole++
+		numerator = 0
+	}
+	return whole, numerator, denominator
+}
+
+// closestFraction returns the fraction closest to a positive
+// number whose denominator is at most maxDenominator. It is
+// found from the convergents of the continued fraction of the
+// number, and the best semiconvergent which follows them.
+func closestFraction(value float64, maxDenominator int) (numerator, denominator int) {
+	// p0/q0 and p1/q1 are the last two convergents
+	p0, q0, p1, q1 := 0, 1, 1, 0
+	x := value
+	for i := 0; i < 64; i++ {
+		a := math.Floor(x)
+		if q1 > 0 && a > float64(maxDenominator) {
+			break
+		}
+		q2 := q0 + int(a)*q1
+		if q2 > maxDenominator {
+			break
+		}
+		p0, q0, p1, q1 = p1, q1, p0+int(a)*p1, q2
+		if x == a {
+			break
+		}
+		x = 1 / (x - a)
+	}
+
+	k := (maxDenominator - q0) / q1
+	p, q := p0+k*p1, q0+k*q1
+	if math.Abs(value-float64(p)/float64(q)) < math.Abs(value-float64(p1)/float64(q1)) {
+		return p, q
+	}
+	return p1, q1
+}
+
+// formatGeneral formats a number as the General format does,
+// with up to 11 characters, not counting the sign.
+func formatGeneral(value float64) string {
+	if value == 0 {
+		return "0"
+	}
+	a := math.Abs(value)
+	if a >= 1e11 || a < 1e-9 {
+		s := strconv.FormatFloat(value, 'E', 5, 64)
+		i := strings.Index(s, "E")
+		mantissa := strings.TrimRight(strings.TrimRight(s[:i], "0"), ".")
+		return mantissa + s[i:]
+	}
+	s := strconv.FormatFloat(a, 'f', -1, 64)
+	if len(s) > 11 {
+		integer := len(strconv.FormatFloat(math.Floor(a), 'f', 0, 64))
+		decimals := 11 - integer - 1
+		if decimals < 0 {
+			decimals = 0
+		}
+		s = strconv.FormatFloat(a, 'f', decimals, 64)
+		if strings.Contains(s, ".") {
+			s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+		}
+	}
+	if value < 0 {
+		return "-" + s
+	}
+	return s
+}
+
+// serialTime returns the time of a date serial number, which
+// counts days from the start of the date system of the workbook.
+// The 1900 date system counts the 29th of February 1900, which
+// did not exist, so serials before it are a day later, and the
+// serial of that day shows the 28th.
+func serialTime(serial float64, date1904 bool, subseconds int) time.Time {
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	switch {
+	case date1904:
+		epoch = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	case serial < 60:
+		epoch = time.Date(1899, 12, 31, 0, 0, 0, 0, time.UTC)
+	}
+	days := math.Floor(serial)
+	unit := math.Pow(10, float64(-subseconds))
+	seconds := math.Round((serial-days)*86400/unit) * unit
+	return epoch.AddDate(0, 0, int(days)).Add(time.Duration(seconds * float64(time.Second)))
+}
+
+// serialDuration returns the time elapsed over a number of days,
+// rounded to the given number of digits of subseconds. Unlike
+// serialTime, it does not depend on the date system.
+func serialDuration(serial float64, subseconds int) time.Duration {
+	unit := math.Pow(10, float64(-subseconds))
+	seconds := math.Round(serial*86400/unit) * unit
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// formatDate formats a date serial number with the section.
+func (fs *formatSection) formatDate(serial float64, date1904 bool) string {
+	t := serialTime(serial, date1904, fs.subseconds)
+	pad := func(n, width int) string {
+		s := strconv.Itoa(n)
+		for len(s) < width {
+			s = "0" + s
+		}
+		return s
+	}
+
+	var b strings.Builder
+	for _, token := range fs.tokens {
+		switch token.kind {
+		case tokenLiteral:
+			b.WriteString(token.text)
+		case tokenDate:
+			n := len(token.text)
+			switch token.text[0] {
+			case 'y':
+				if n <= 2 {
+					b.WriteString(pad(t.Year()%100, 2))
+				} else {
+					b.WriteString(pad(t.Year(), 4))
+				}
+			case 'm':
+				switch {
+				case n <= 2:
+					b.WriteString(pad(int(t.Month()), n))
+				case n == 3:
+					b.WriteString(t.Month().String()[:3])
+				case n == 5:
+					b.WriteString(t.Month().String()[:1])
+				default:
+					b.WriteString(t.Month().String())
+				}
+			case 'd':
+				switch {
+				case n <= 2:
+					b.WriteString(pad(t.Day(), n))
+				case n == 3:
+					b.WriteString(t.Weekday().String()[:3])
+				default:
+					b.WriteString(t.Weekday().String())
+				}
+			case 'h':
+				hour := t.Hour()
+				if fs.hasAmPm {
+					hour = (hour+11)%12 + 1
+				}
+				b.WriteString(pad(hour, min(n, 2)))
+			case 'n':
+				b.WriteString(pad(t.Minute(), min(n, 2)))
+			case 's':
+				b.WriteString(pad(t.Second(), min(n, 2)))
+			}
+		case tokenElapsed:
+			elapsed := serialDuration(serial, fs.subseconds)
+			switch token.text[0] {
+			case 'h':
+				b.WriteString(pad(int(elapsed.Hours()), len(token.text)))
+			case 'm':
+				b.WriteString(pad(int(elapsed.Minutes()), len(token.text)))
+			case 's':
+				b.WriteString(pad(int(elapsed.Seconds()), len(token.text)))
+			}
+		case tokenSubsecond:
+			nanos := strconv.Itoa(t.Nanosecond() + int(time.Second))[1:]
+			b.WriteString("." + nanos[:fs.subseconds])
+		case tokenAmPm:
+			am, pm := "AM", "PM"
+			if len(token.text) == 3 {
+				am, pm = token.text[:1], token.text[2:]
+			} else if token.text[0] == 'a' {
+				am, pm = "am", "pm"
+			}
+			if t.Hour() < 12 {
+				b.WriteString(am)
+			} else {
+				b.WriteString(pm)
+			}
+		case tokenDigit:
+			b.WriteString(token.text)
+		case tokenDecimal:
+			b.WriteString(".")
+		}
+	}
+	return b.String()
+}
+
+// formatDateValue formats the ISO 8601 value of a date cell
+// with the section, by turning it into a date serial number.
+// Times without a date are the fraction of a day they make up,
+// whatever the date system.
+func formatDateValue(nf *numberFormat, value string, date1904 bool) (string, bool) {
+	layouts := []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02", "15:04:05"}
+	for _, layout := range layouts {
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+		if layout == "15:04:05" {
+			day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+			return nf.formatNumber(t.Sub(day).Hours()/24, date1904)
+		}
+		epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+		if date1904 {
+			epoch = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+		}
+		serial := t.Sub(epoch).Hours() / 24
+		if !date1904 && serial < 61 {
+			serial--
+		}
+		return nf.formatNumber(serial, date1904)
+	}
+	return "", false
+}
+
+// min returns the smaller of two integers.
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// max returns the larger of two integers.
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// abs returns the absolute value of an integer.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,193 @@
+package xlsx
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+)
+
+func TestNumberFormat_FormatNumber(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		value    float64
+		date1904 bool
+		want     string
+	}{
+		{"general integer", "General", 43817, false, "43817"},
+		{"general repeating", "General", 1.0 / 3, false, "0.333333333"},
+		{"general rounding error", "General", 0.1 + 0.2, false, "0.3"},
+		{"general large", "General", 123456789012, false, "1.23457E+11"},
+		{"rounded", "0", 3.5, false, "4"},
+		{"decimals negative", "0.00", -3.14159, false, "-3.14"},
+		{"thousands", "#,##0", 1234567.8, false, "1,234,568"},
+		{"thousands decimals", "#,##0.00", -1234.5, false, "-1,234.50"},
+		{"scaled", `#,##0,"K"`, 1234567, false, "1,235K"},
+		{"optional decimals", "#.##", 1, false, "1."},
+		{"aligned decimals", "0.0?", 1.5, false, "1.5 "},
+		{"digit literals", "000-00-0000", 123456789, false, "123-45-6789"},
+		{"percent", "0%", 0.256, false, "26%"},
+		{"percent decimals", "0.00%", 0.256, false, "25.60%"},
+		{"scientific", "0.00E+00", 12345, false, "1.23E+04"},
+		{"scientific small", "0.00E+00", 0.00012, false, "1.20E-04"},
+		{"scientific rounded up", "0.00E+00", 9.999, false, "1.00E+01"},
+		{"scientific rounded up large", "0.00E+00", 99999, false, "1.00E+05"},
+		{"engineering rounded up", "##0.0E+0", 999.96, false, "1.0E+3"},
+		{"engineering", "##0.0E+0", 12345, false, "12.3E+3"},
+		{"fraction", "# ?/?", 1.5, false, "1 1/2"},
+		{"fraction two digits", "# ??/??", 3.14159, false, "3 14/99"},
+		{"fraction whole", "# ?/?", 5, false, "5    "},
+		{"fraction improper", "?/?", 0.75, false, "3/4"},
+		{"fraction improper above one", "?/?", 12.5, false, "25/2"},
+		{"fraction five digits", "# ?????/?????", 3.14159265358979, false, "3 14093/99532"},
+		{"fraction capped digits", "????????????/????????????", 1.75, false, "           7/4           "},
+		{"fraction quarters", "# ?/4", 2.3, false, "2 1/4"},
+		{"currency", `"£"#,##0.00`, 1234.5, false, "£1,234.50"},
+		{"currency negative", `"£"#,##0.00`, -1234.5, false, "-£1,234.50"},
+		{"currency locale", "[$€-407] #,##0.00", 5, false, "€ 5.00"},
+		{"negative section", "#,##0 ;(#,##0)", -5, false, "(5)"},
+		{"accounting negative", builtInNumFmts[44], -5, false, " $(5.00)"},
+		{"accounting zero", builtInNumFmts[44], 0, false, " $-   "},
+		{"conditions", `[Red][<0]0.0;[>=100]"big";0`, 150, false, "big"},
+		{"short date", "m/d/yyyy", 43817, false, "12/18/2019"},
+		{"short date 1904", "m/d/yyyy", 42355, true, "12/18/2019"},
+		{"first date", "m/d/yyyy", 1, false, "1/1/1900"},
+		{"after leap year bug", "m/d/yyyy", 61, false, "3/1/1900"},
+		{"month names", "d-mmm-yy", 43817, false, "18-Dec-19"},
+		{"long date", "dddd, mmmm d, yyyy", 43817, false, "Wednesday, December 18, 2019"},
+		{"time AM/PM", "h:mm AM/PM", 0.75, false, "6:00 PM"},
+		{"time rounded", "h:mm:ss", 0.5000115, false, "12:00:01"},
+		{"date time", "m/d/yyyy h:mm", 43817.5, false, "12/18/2019 12:00"},
+		{"elapsed hours", "[h]:mm:ss", 1.5, false, "36:00:00"},
+		{"elapsed before leap year bug", "[h]:mm:ss", 59, false, "1416:00:00"},
+		{"elapsed after leap year bug", "[h]:mm:ss", 61, false, "1464:00:00"},
+		{"elapsed 1904", "[h]:mm:ss", 100, true, "2400:00:00"},
+		{"elapsed minutes", "[mm]:ss", 61.5, false, "88560:00"},
+		{"subseconds", "mm:ss.0", 0.00001, false, "00:00.9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseNumFmt(tt.code).formatNumber(tt.value, tt.date1904)
+			if !ok || got != tt.want {
+				t.Fatalf("expected: \n%q\n\ngot: \n%q\n", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFormatDateValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		value    string
+		date1904 bool
+		want     string
+	}{
+		{"date", "m/d/yyyy", "2019-12-18", false, "12/18/2019"},
+		{"date 1904", "m/d/yyyy", "2019-12-18", true, "12/18/2019"},
+		{"before leap year bug", "m/d/yyyy", "1900-02-28", false, "2/28/1900"},
+		{"time", "h:mm:ss", "06:30:15", false, "6:30:15"},
+		{"time 1904", "h:mm:ss", "06:30:15", true, "6:30:15"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := formatDateValue(parseNumFmt(tt.code), tt.value, tt.date1904)
+			if !ok || got != tt.want {
+				t.Fatalf("expected: \n%q\n\ngot: \n%q\n", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNumberFormat_FormatText(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want string
+		ok   bool
+	}{
+		{"text section", `0;-0;0;"<"@">"`, "<text>", true},
+		{"text only", `@" units"`, "text units", true},
+		{"no text section", "0.00", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseNumFmt(tt.code).formatText("text")
+			if ok != tt.ok || got != tt.want {
+				t.Fatalf("expected: \n%q %v\n\ngot: \n%q %v\n", tt.want, tt.ok, got, ok)
+			}
+		})
+	}
+}
+
+func TestMakeTextMatrix_NumberFormats(t *testing.T) {
+	stylesXML := `<styleSheet>
+<numFmts count="2">
+	<numFmt numFmtId="164" formatCode="&quot;£&quot;#,##0.00"/>
+	<numFmt numFmtId="x" formatCode="0.00"/>
+</numFmts>
+<cellXfs count="6">
+	<xf numFmtId="0"/>
+	<xf numFmtId="14"/>
+	<xf numFmtId="10"/>
+	<xf numFmtId="164"/>
+	<xf numFmtId="22"/>
+	<xf numFmtId="x"/>
+</cellXfs>
+</styleSheet>`
+	sheetXML := `<worksheet><sheetData>
+<row r="1">
+	<c r="A1"><v>0.30000000000000004</v></c>
+	<c r="B1" s="1"><v>43817</v></c>
+	<c r="C1" s="2"><v>0.125</v></c>
+	<c r="D1" s="3"><v>1234.5</v></c>
+	<c r="E1" s="4" t="d"><v>2019-12-18T06:30:00</v></c>
+	<c r="F1" s="9"><v>0.125</v></c>
+	<c r="G1" s="5"><v>0.125</v></c>
+</row>
+</sheetData></worksheet>`
+
+	var styles StyleSheet
+	if err := xml.Unmarshal([]byte(stylesXML), &styles); err != nil {
+		t.Fatalf("could not decode styles: %v", err)
+	}
+	var sheet WorkSheet
+	if err := xml.Unmarshal([]byte(sheetXML), &sheet); err != nil {
+		t.Fatalf("could not decode work sheet: %v", err)
+	}
+	formats := makeCellFormats(styles)
+
+	tests := []struct {
+		name     string
+		date1904 bool
+		want     [][]string
+	}{
+		{
+			name: "1900 date system",
+			want: [][]string{{"0.3", "12/18/2019", "12.50%", "£1,234.50", "12/18/2019 6:30", "0.125", "0.125"}},
+		},
+		{
+			name:     "1904 date system",
+			date1904: true,
+			want:     [][]string{{"0.3", "12/19/2023", "12.50%", "£1,234.50", "12/18/2019 6:30", "0.125", "0.125"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := newCellReader(nil, Config{})
+			reader.formats = formats
+			reader.date1904 = tt.date1904
+			got, err := makeTextMatrix(sheet, reader)
+			if err != nil {
+				t.Fatalf("could not make text matrix: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected: \n%q\n\ngot: \n%q\n", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package xlsx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// RowIterator reads the rows of a work sheet one at a time,
+// decoding the XML of each row as it is reached, so that only
+// one row of the sheet is held in memory. RowIterator should
+// only be constructed with the NewRowIterator function.
+type RowIterator struct {
+	dec    *xml.Decoder
+	reader *cellReader
+
+	// sheet collects the parts of the work
+	// sheet which come before its rows.
+	sheet WorkSheet
+	width int
+
+	y             int
+	pending       []string
+	pendingY      int
+	pendingHidden bool
+	hidden        bool
+	done          bool
+}
+
+// NewRowIterator creates a RowIterator which reads the XML of
+// a work sheet from r, and looks up shared strings in lookup.
+// Cells are written as MakeTextMatrix writes them.
+func NewRowIterator(r io.Reader, lookup SharedStringLookup) *RowIterator {
+	return newRowIterator(r, newCellReader(lookup, Config{}))
+}
+
+// newRowIterator creates a RowIterator which uses
+// the cellReader to write the value of each cell.
+func newRowIterator(r io.Reader, reader *cellReader) *RowIterator {
+	return &RowIterator{
+		dec:    xml.NewDecoder(r),
+		reader: reader,
+	}
+}
+
+// Width returns the number of columns of the work sheet given
+// by its dimension, or 0 if it has no usable dimension, see
+// dimensionSize. The dimension is read by the first call to
+// Next.
+func (it *RowIterator) Width() int {
+	return it.width
+}
+
+// Next returns the next row of the work sheet, starting from
+// the first row. Rows missing from the sheet are returned
+// empty. Rows are as wide as the dimension of the sheet, or
+// as wide as their last cell if it is wider, or if the sheet
+// has no usable dimension. io.EOF is returned after the last
+// row.
+func (it *RowIterator) Next() ([]string, error) {
+	if it.pending == nil && !it.done {
+		if err := it.decodeRow(); err != nil {
+			return nil, err
+		}
+	}
+	if it.pending == nil {
+		return nil, io.EOF
+	}
+
+	y := it.y
+	it.y++
+	if y < it.pendingY {
+		it.hidden = false
+		return make([]string, it.width), nil
+	}
+	row := it.pending
+	it.pending = nil
+	it.hidden = it.pendingHidden
+	return row, nil
+}
+
+// rowHidden reports whether the row last
+// returned by Next is hidden.
+func (it *RowIterator) rowHidden() bool {
+	return it.hidden
+}
+
+// decodeRow decodes tokens up to the end of the next row
+// and sets it as pending, collecting the parts of the work
+// sheet it passes on the way. done is set at the end of
+// the sheet.
+func (it *RowIterator) decodeRow() error {
+	for {
+		token, err := it.dec.Token()
+		if err == io.EOF {
+			it.done = true
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "dimension":
+			for _, attr := range start.Attr {
+				if attr.Name.Local == "ref" {
+					it.width, _, _ = dimensionSize(attr.Value)
+				}
+			}
+		case "sheetFormatPr":
+			if err := it.dec.DecodeElement(&it.sheet.SheetFormat, &start); err != nil {
+				return err
+			}
+		case "cols":
+			var cols struct {
+				Cols []col `xml:"col"`
+			}
+			if err := it.dec.DecodeElement(&cols, &start); err != nil {
+				return err
+			}
+			it.sheet.Cols = append(it.sheet.Cols, cols.Cols...)
+		case "row":
+			var r row
+			if err := it.dec.DecodeElement(&r, &start); err != nil {
+				return err
+			}
+			return it.setPending(r)
+		}
+	}
+}
+
+// setPending writes the text of the cells of a row, and sets
+// it as the pending row, to be returned once the rows before
+// it have been.
+func (it *RowIterator) setPending(r row) error {
+	y, cellXs, err := rowPosition(r, it.y-1)
+	if err != nil {
+		return err
+	}
+	if y < it.y {
+		return fmt.Errorf("row %v is out of order", y+1)
+	}
+
+	text := make([]string, it.width)
+	for j, c := range r.Cells {
+		value, err := it.reader.text(c)
+		if err != nil {
+			return fmt.Errorf("could not read cell %v: %v", cellName(cellXs[j], y), err)
+		}
+		for cellXs[j] >= len(text) {
+			text = append(text, "")
+		}
+		text[cellXs[j]] = value
+	}
+
+	it.pending = text
+	it.pendingY = y
+	it.pendingHidden = isSet(r.Hidden)
+	return nil
+}
@@ -0,0 +1,177 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"github.com/kinluek/texttable"
+	"io"
+)
+
+// tableSpacing separates the string tables of
+// the work sheets written by ExtractTo.
+const tableSpacing = "\n\n"
+
+// ExtractTo works like ExtractReader, but writes the string
+// table of each extracted work sheet to w as the rows of the
+// sheet are read, rather than returning the tables, so that
+// very large sheets can be extracted without holding them in
+// memory. The tables are separated by a blank line, and are
+// written as a texttable.Writer writes them. The tables are as
+// wide as the dimension of their sheet, so cells outside of it
+// are left out. Sheets without a usable dimension, see
+// dimensionSize, are read twice, first to find how many
+// columns their cells take up, without reading the cells.
+// Merged cells are listed after the rows of a sheet, so
+// MergedCells is not used.
+func ExtractTo(w io.Writer, r io.ReaderAt, size int64, config Config) error {
+	book, err := openArchive(r, size, config)
+	if err != nil {
+		return err
+	}
+
+	written := 0
+	for _, info := range book.sheets {
+		extracted, err := config.extracts(info)
+		if err != nil {
+			return err
+		}
+		if !extracted {
+			continue
+		}
+
+		workSheet, ok := book.zipFiles[info.partName]
+		if !ok {
+			return fmt.Errorf("missing work sheet %v", info.partName)
+		}
+		if written > 0 {
+			if _, err := io.WriteString(w, tableSpacing); err != nil {
+				return err
+			}
+		}
+		if err := streamSheet(w, workSheet, info, book.cellReader, config); err != nil {
+			return fmt.Errorf("could not extract file %v: %v", info.partName, err)
+		}
+		written++
+	}
+	return nil
+}
+
+// streamSheet writes the string table of a work sheet to w,
+// one row at a time.
+func streamSheet(w io.Writer, f *zip.File, info sheetInfo, reader *cellReader, config Config) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	it := newRowIterator(rc, reader)
+
+	// the first row is read before the table is set up,
+	// to read the parts of the sheet which come before it.
+	row, err := it.Next()
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	width := it.Width()
+	if err == io.EOF {
+		width = 0
+	} else if width == 0 {
+		if width, err = scanWidth(f); err != nil {
+			return err
+		}
+	}
+
+	columns, err := hiddenColumns(it.sheet)
+	if err != nil {
+		return err
+	}
+	tableConfig, err := config.tableConfig(it.sheet, info, width)
+	if err != nil {
+		return err
+	}
+	tableWidth := width
+	if config.Hidden == HiddenExclude {
+		for x := 0; x < width; x++ {
+			if columns.contains(x) {
+				tableWidth--
+			}
+		}
+	}
+
+	tw := texttable.NewWriter(w, tableWidth, tableConfig)
+	for ; err == nil; row, err = it.Next() {
+		// rows are as wide as the dimension of the sheet,
+		// or as their last cell if it lies outside of it.
+		if len(row) > width {
+			row = row[:width]
+		} else if len(row) < width {
+			padded := make([]string, width)
+			copy(padded, row)
+			row = padded
+		}
+		row, kept := hideRow(row, it.rowHidden(), columns, config.Hidden, config.hiddenMarker())
+		if !kept {
+			continue
+		}
+		if err := tw.WriteRow(row); err != nil {
+			return err
+		}
+	}
+	if err != io.EOF {
+		return err
+	}
+	return tw.Close()
+}
+
+// scanWidth returns the number of columns of a work sheet up
+// to the last cell of its rows. Only the positions of the rows
+// and cells are decoded, not their values.
+func scanWidth(f *zip.File) (int, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+
+	dec := xml.NewDecoder(rc)
+	width, y := 0, -1
+	for {
+		token, err := dec.Token()
+		if err == io.EOF {
+			return width, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+
+		var position struct {
+			R     string `xml:"r,attr"`
+			Cells []struct {
+				Coordinate string `xml:"r,attr"`
+			} `xml:"c"`
+		}
+		if err := dec.DecodeElement(&position, &start); err != nil {
+			return 0, err
+		}
+		r := row{R: position.R, Cells: make([]cell, len(position.Cells))}
+		for j, c := range position.Cells {
+			r.Cells[j].Coordinate = c.Coordinate
+		}
+
+		var cellXs []int
+		if y, cellXs, err = rowPosition(r, y); err != nil {
+			return 0, err
+		}
+		for _, x := range cellXs {
+			if x >= width {
+				width = x + 1
+			}
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package xlsx
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRowIterator_Next(t *testing.T) {
+	tests := []struct {
+		name      string
+		sheetXML  string
+		wantWidth int
+		want      [][]string
+		hidden    []bool
+	}{
+		{
+			name: "dimension and missing rows",
+			sheetXML: `<worksheet><dimension ref="A1:C4"/><sheetData>
+<row r="2"><c r="B2" t="inlineStr"><is><t>b</t></is></c></row>
+<row r="4" hidden="1"><c r="A4"><v>1</v></c><c r="C4"><v>2</v></c></row>
+</sheetData></worksheet>`,
+			wantWidth: 3,
+			want: [][]string{
+				{"", "", ""},
+				{"", "b", ""},
+				{"", "", ""},
+				{"1", "", "2"},
+			},
+			hidden: []bool{false, false, false, true},
+		},
+		{
+			name: "inflated dimension",
+			sheetXML: `<worksheet><dimension ref="A1:XFD1048576"/><sheetData>
+<row r="1"><c r="A1"><v>1</v></c></row>
+<row r="3"><c r="B3"><v>2</v></c></row>
+</sheetData></worksheet>`,
+			want: [][]string{
+				{"1"},
+				{},
+				{"", "2"},
+			},
+			hidden: []bool{false, false, false},
+		},
+		{
+			name: "no dimension",
+			sheetXML: `<worksheet><sheetData>
+<row r="1"><c r="A1"><v>1</v></c></row>
+<row r="2"><c r="A2"><v>2</v></c><c r="C2"><v>3</v></c></row>
+</sheetData></worksheet>`,
+			want: [][]string{
+				{"1"},
+				{"2", "", "3"},
+			},
+			hidden: []bool{false, false},
+		},
+		{
+			name: "no coordinates",
+			sheetXML: `<worksheet><sheetData>
+<row><c><v>1</v></c><c><v>2</v></c></row>
+<row><c><v>3</v></c></row>
+</sheetData></worksheet>`,
+			want: [][]string{
+				{"1", "2"},
+				{"3"},
+			},
+			hidden: []bool{false, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			it := NewRowIterator(strings.NewReader(tt.sheetXML), nil)
+			var got [][]string
+			var hidden []bool
+			for {
+				row, err := it.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("could not read row: %v", err)
+				}
+				got = append(got, row)
+				hidden = append(hidden, it.rowHidden())
+			}
+			if it.Width() != tt.wantWidth {
+				t.Fatalf("expected: \n%v\n\ngot: \n%v\n", tt.wantWidth, it.Width())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected: \n%q\n\ngot: \n%q\n", tt.want, got)
+			}
+			if !reflect.DeepEqual(hidden, tt.hidden) {
+				t.Fatalf("expected: \n%v\n\ngot: \n%v\n", tt.hidden, hidden)
+			}
+		})
+	}
+}
+
+func TestExtractTo(t *testing.T) {
+	inputPaths := []string{
+		"./testdata/xlsx_files/simple_spread_sheet_1/simple_spread_sheet_1.xlsx",
+		"./testdata/xlsx_files/simple_spread_sheet_2/simple_spread_sheet_2.xlsx",
+		"./testdata/xlsx_files/two_page_spread_sheet_1/two_page_spread_sheet_1.xlsx",
+	}
+	configs := []Config{
+		{ColumnWidth: 30, ColumnMargin: 2, RowMargin: 1, SheetNameTitle: true},
+		{ColumnMargin: 1, RowMargin: 1, SheetColumnWidths: true, TableWidth: 120, Hidden: HiddenExclude},
+	}
+
+	for _, inputPath := range inputPaths {
+		data, err := ioutil.ReadFile(inputPath)
+		if err != nil {
+			t.Fatalf("could not read input file: %v", err)
+		}
+		for _, config := range configs {
+			sheets, err := ExtractBytes(data, config)
+			if err != nil {
+				t.Fatalf("could not extract text: %v", err)
+			}
+			var tables []string
+			for _, sheet := range sheets {
+				tables = append(tables, sheet.Text)
+			}
+			want := strings.Join(tables, tableSpacing)
+
+			var b bytes.Buffer
+			if err := ExtractTo(&b, bytes.NewReader(data), int64(len(data)), config); err != nil {
+				t.Fatalf("could not extract text to writer: %v", err)
+			}
+			if got := b.String(); got != want {
+				t.Fatalf("expected: \n%v\n\ngot: \n%v\n", want, got)
+			}
+		}
+	}
+}
+
+func TestExtractTo_Dimensions(t *testing.T) {
+	tests := []struct {
+		name     string
+		sheetXML string
+		// wantXML is the sheet which extracts to the streamed
+		// table, if it is not sheetXML. Cells outside of the
+		// dimension of a sheet are left out when streaming.
+		wantXML string
+	}{
+		{
+			name: "understated",
+			sheetXML: `<worksheet><dimension ref="A1"/><sheetData>
+<row r="1"><c r="A1"><v>1</v></c><c r="B1"><v>2</v></c></row>
+</sheetData></worksheet>`,
+			wantXML: `<worksheet><dimension ref="A1"/><sheetData>
+<row r="1"><c r="A1"><v>1</v></c></row>
+</sheetData></worksheet>`,
+		},
+		{
+			name: "inflated",
+			sheetXML: `<worksheet><dimension ref="A1:XFD1048576"/><sheetData>
+<row r="1"><c r="A1"><v>1</v></c></row>
+</sheetData></worksheet>`,
+		},
+		{
+			name: "malformed",
+			sheetXML: `<worksheet><dimension ref="A1:9Z"/><sheetData>
+<row r="1"><c r="A1"><v>1</v></c></row>
+<row r="2"><c r="C2"/></row>
+</sheetData></worksheet>`,
+		},
+		{
+			name:     "empty",
+			sheetXML: `<worksheet><dimension ref="A1"/><sheetData/></worksheet>`,
+		},
+		{
+			name: "empty cells",
+			sheetXML: `<worksheet><dimension ref="A1:C2"/><sheetData>
+<row r="1"><c r="A1"><v>1</v></c></row>
+<row r="2"><c r="A2"><v>2</v></c><c r="C2"/></row>
+</sheetData></worksheet>`,
+		},
+		{
+			name: "no dimension",
+			sheetXML: `<worksheet><sheetData>
+<row><c><v>1</v></c></row>
+<row><c><v>2</v></c><c r="C2" t="inlineStr"><is><t>3</t></is></c></row>
+</sheetData></worksheet>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{ColumnWidth: 5, ColumnMargin: 1, RowMargin: 1}
+			wantXML := tt.wantXML
+			if wantXML == "" {
+				wantXML = tt.sheetXML
+			}
+
+			extracts, err := ExtractBytes(workbookFiles(t, testSheet{name: "Sheet1", xml: wantXML}), config)
+			if err != nil {
+				t.Fatalf("could not extract text: %v", err)
+			}
+			var tables []string
+			for _, extract := range extracts {
+				tables = append(tables, extract.Text)
+			}
+			want := strings.Join(tables, tableSpacing)
+
+			data := workbookFiles(t, testSheet{name: "Sheet1", xml: tt.sheetXML})
+			var b bytes.Buffer
+			if err := ExtractTo(&b, bytes.NewReader(data), int64(len(data)), config); err != nil {
+				t.Fatalf("could not extract text to writer: %v", err)
+			}
+			if got := b.String(); got != want {
+				t.Fatalf("expected: \n%q\n\ngot: \n%q\n", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"fmt"
+	"strconv"
+)
+
+const fileTypeStyles = "application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"
+
+// readStyles decodes the styles file, if the workbook has
+// one, and returns the number format of each cell format,
+// in the order the s attribute of cells refers to them.
+func readStyles(zipFiles map[string]*zip.File, fileDesc ContentTypes) ([]*numberFormat, error) {
+	var styles StyleSheet
+	for _, or := range fileDesc.Overrides {
+		if or.ContentType != fileTypeStyles {
+			continue
+		}
+		stylesFile, ok := zipFiles[or.PartName]
+		if !ok {
+			return nil, fmt.Errorf("missing styles file %v", or.PartName)
+		}
+		if err := decodeZipFile(stylesFile, &styles); err != nil {
+			return nil, fmt.Errorf("could not decode file %v: %v", or.PartName, err)
+		}
+	}
+	return makeCellFormats(styles), nil
+}
+
+// makeCellFormats parses the number format of each cell
+// format of the style sheet. Formats defined by the workbook
+// override the built-in formats with the same ID. IDs which
+// are neither, or which cannot be parsed, use the General
+// format, as do the formats defined with such IDs.
+func makeCellFormats(styles StyleSheet) []*numberFormat {
+	codes := make(map[int]string)
+	for id, code := range builtInNumFmts {
+		codes[id] = code
+	}
+	for _, nf := range styles.NumFmts {
+		if id, err := strconv.Atoi(nf.NumFmtID); err == nil {
+			codes[id] = nf.FormatCode
+		}
+	}
+
+	parsed := make(map[string]*numberFormat)
+	formats := make([]*numberFormat, len(styles.CellXfs))
+	for i, xf := range styles.CellXfs {
+		code := builtInNumFmts[0]
+		if id, err := strconv.Atoi(xf.NumFmtID); err == nil {
+			if c, ok := codes[id]; ok {
+				code = c
+			}
+		}
+		if _, ok := parsed[code]; !ok {
+			parsed[code] = parseNumFmt(code)
+		}
+		formats[i] = parsed[code]
+	}
+	return formats
+}
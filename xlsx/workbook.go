@@ -0,0 +1,115 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+var errMissingWorkbook = errors.New("missing workbook file")
+
+const (
+	fileTypeWorkbook          = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"
+	fileTypeWorkbookMacro     = "application/vnd.ms-excel.sheet.macroEnabled.main+xml"
+	fileTypeWorkbookTemplate  = "application/vnd.openxmlformats-officedocument.spreadsheetml.template.main+xml"
+	relationshipTypeWorkSheet = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet"
+
+	fileNameWorkbook = "/xl/workbook.xml"
+
+	// sheetStateVisible is the state of work sheets
+	// which do not set one in the workbook.
+	sheetStateVisible = "visible"
+)
+
+// sheetInfo holds what the workbook records about a
+// work sheet, and the part name of its file.
+type sheetInfo struct {
+	partName string
+	name     string
+	id       int
+	index    int
+	state    string
+}
+
+// readWorkbook decodes the workbook and its relationships
+// file, and returns the work sheets listed in the workbook,
+// in the order of their tabs, and whether its dates use the
+// 1904 date system.
+func readWorkbook(zipFiles map[string]*zip.File, fileDesc ContentTypes) ([]sheetInfo, bool, error) {
+	workbookName := fileNameWorkbook
+	for _, or := range fileDesc.Overrides {
+		switch or.ContentType {
+		case fileTypeWorkbook, fileTypeWorkbookMacro, fileTypeWorkbookTemplate:
+			workbookName = or.PartName
+		}
+	}
+
+	workbookFile, ok := zipFiles[workbookName]
+	if !ok {
+		return nil, false, errMissingWorkbook
+	}
+	var workbook Workbook
+	if err := decodeZipFile(workbookFile, &workbook); err != nil {
+		return nil, false, fmt.Errorf("could not decode file %v: %v", workbookName, err)
+	}
+
+	// the relationships of a file are kept in the
+	// _rels directory next to it.
+	dir, file := path.Split(workbookName)
+	relsName := path.Join(dir, "_rels", file+".rels")
+	relsFile, ok := zipFiles[relsName]
+	if !ok {
+		return nil, false, fmt.Errorf("missing relationships file %v", relsName)
+	}
+	var rels Relationships
+	if err := decodeZipFile(relsFile, &rels); err != nil {
+		return nil, false, fmt.Errorf("could not decode file %v: %v", relsName, err)
+	}
+	targets := make(map[string]string)
+	for _, rel := range rels.Relationships {
+		if rel.Type == relationshipTypeWorkSheet {
+			targets[rel.ID] = partName(dir, rel.Target)
+		}
+	}
+
+	sheets := make([]sheetInfo, 0, len(workbook.Sheets))
+	for i, sheet := range workbook.Sheets {
+		target, ok := targets[sheet.RelID]
+		if !ok {
+			// chart sheets and dialog sheets are listed
+			// in the workbook, but are not work sheets.
+			continue
+		}
+		id, err := strconv.Atoi(sheet.SheetID)
+		if err != nil {
+			return nil, false, fmt.Errorf("could not parse sheet ID %v of %v: %v", sheet.SheetID, sheet.Name, err)
+		}
+		state := sheet.State
+		if state == "" {
+			state = sheetStateVisible
+		}
+		sheets = append(sheets, sheetInfo{
+			partName: target,
+			name:     sheet.Name,
+			id:       id,
+			index:    i,
+			state:    state,
+		})
+	}
+	date1904 := workbook.Properties.Date1904 == "1" || workbook.Properties.Date1904 == "true"
+	return sheets, date1904, nil
+}
+
+// partName returns the part name of the target of a
+// relationship of a file in the given directory.
+// Targets are relative to the directory, unless they
+// start with '/'.
+func partName(dir, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return path.Clean(target)
+	}
+	return path.Join("/", dir, target)
+}
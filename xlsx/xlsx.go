@@ -7,10 +7,13 @@ import (
 	"errors"
 	"fmt"
 	"github.com/kinluek/texttable"
+	"io"
 	"math"
 	"os"
+	"path"
 	"regexp"
 	"strconv"
+	"strings"
 )
 
 var (
@@ -20,16 +23,26 @@ var (
 
 const (
 	fileTypeSharedStrings = "application/vnd.openxmlformats-officedocument.spreadsheetml.sharedStrings+xml"
-	fileTypeWorkSheet     = "application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"
 
 	fileNameContentTypes = "[Content_Types].xml"
 )
 
 // WorkSheetExtract contains a formatted work sheet string
-// and its corresponding sheet number.
+// and the details of its sheet from the workbook.
 type WorkSheetExtract struct {
+	// SheetName is the name of the sheet shown on its tab.
 	SheetName string
-	Text      string
+	// SheetID is the ID of the sheet in the workbook.
+	SheetID int
+	// Index is the position of the sheet's tab in the
+	// workbook, counting from 0.
+	Index int
+	// State is "visible", "hidden" or "veryHidden".
+	State string
+	// Path is the path of the work sheet file in the
+	// XLSX zip archive.
+	Path string
+	Text string
 }
 
 // Config should be used to specify output table
@@ -38,18 +51,151 @@ type Config struct {
 	ColumnWidth  int
 	ColumnMargin int
 	RowMargin    int
+
+	// SheetColumnWidths takes the width of each column from
+	// the column definitions of the work sheet, rather than
+	// from ColumnWidth, so that the table keeps the proportions
+	// of the sheet. Excel widths count characters, and are scaled
+	// so that the table is TableWidth wide, margins included, if
+	// TableWidth is set.
+	SheetColumnWidths bool
+	TableWidth        int
+
+	// SheetNameTitle sets the name of each work
+	// sheet as the title of its string table.
+	SheetNameTitle bool
+
+	// SheetNames and SheetIndexes select the work sheets
+	// which are extracted. A sheet is selected if its name
+	// matches one of the names, which may be glob patterns,
+	// as used by path.Match, or if its index is one of the
+	// indexes. Every sheet is extracted if neither is set.
+	SheetNames   []string
+	SheetIndexes []int
+
+	// TrueText and FalseText are written for boolean cells,
+	// and default to "TRUE" and "FALSE". ErrorText maps error
+	// values, such as "#N/A" or "#DIV/0!", to the text written
+	// for them. Errors which are not mapped are written as
+	// they are.
+	TrueText  string
+	FalseText string
+	ErrorText map[string]string
+
+	// MergedCells sets how merged cells are extracted,
+	// and defaults to MergeIgnore.
+	MergedCells MergeMode
+
+	// Hidden sets how hidden rows, columns and sheets are
+	// extracted, and defaults to HiddenInclude. HiddenMarker
+	// is written before hidden content when it is marked, and
+	// defaults to "(hidden)".
+	Hidden       HiddenMode
+	HiddenMarker string
 }
 
 // Extract takes an *os.File which should contain the zipped
-// XLSX data and returns the extracted content as a formatted
-// string table. It takes a second parameter which sets the column
+// XLSX data and returns the extracted content of each work
+// sheet as a formatted string table, in the order of the
+// sheet tabs. It takes a second parameter which sets the column
 // width and column and row margins of the generated string tables.
 func Extract(file *os.File, config Config) ([]WorkSheetExtract, error) {
 	fi, err := file.Stat()
 	if err != nil {
 		return nil, err
 	}
-	zr, err := zip.NewReader(file, fi.Size())
+	return ExtractReader(file, fi.Size(), config)
+}
+
+// ExtractBytes works like Extract, reading the
+// zipped XLSX data from a byte slice.
+func ExtractBytes(data []byte, config Config) ([]WorkSheetExtract, error) {
+	return ExtractReader(bytes.NewReader(data), int64(len(data)), config)
+}
+
+// ExtractReader works like Extract, reading the zipped
+// XLSX data of the given size from an io.ReaderAt, such
+// as an uploaded file or a blob held in memory.
+func ExtractReader(r io.ReaderAt, size int64, config Config) ([]WorkSheetExtract, error) {
+	book, err := openArchive(r, size, config)
+	if err != nil {
+		return nil, err
+	}
+
+	workSheetExtracts := make([]WorkSheetExtract, 0)
+
+	for _, info := range book.sheets {
+		extracted, err := config.extracts(info)
+		if err != nil {
+			return nil, err
+		}
+		if !extracted {
+			continue
+		}
+
+		workSheet, ok := book.zipFiles[info.partName]
+		if !ok {
+			return nil, fmt.Errorf("missing work sheet %v", info.partName)
+		}
+		var sheet WorkSheet
+		if err := decodeZipFile(workSheet, &sheet); err != nil {
+			return nil, fmt.Errorf("could not decode file %v: %v", info.partName, err)
+		}
+
+		textMatrix, err := makeTextMatrix(sheet, book.cellReader)
+		if err != nil {
+			return nil, fmt.Errorf("could not create text table: %v", err)
+		}
+		spans, err := applyMerges(textMatrix, sheet, config.MergedCells)
+		if err != nil {
+			return nil, fmt.Errorf("could not merge cells of %v: %v", info.partName, err)
+		}
+		width := 0
+		if len(textMatrix) > 0 {
+			width = len(textMatrix[0])
+		}
+		textMatrix, spans, err = applyHidden(textMatrix, spans, sheet, config.Hidden, config.hiddenMarker())
+		if err != nil {
+			return nil, fmt.Errorf("could not hide cells of %v: %v", info.partName, err)
+		}
+
+		tableConfig, err := config.tableConfig(sheet, info, width)
+		if err != nil {
+			return nil, err
+		}
+		tableConfig.Spans = spans
+		ttf := texttable.New(textMatrix, tableConfig)
+		stringTable, err := ttf.Output()
+		if err != nil {
+			return nil, fmt.Errorf("could not format text table into string: %v", err)
+		}
+
+		workSheetExtracts = append(workSheetExtracts, WorkSheetExtract{
+			SheetName: info.name,
+			SheetID:   info.id,
+			Index:     info.index,
+			State:     info.state,
+			Path:      workSheet.Name,
+			Text:      stringTable,
+		})
+	}
+
+	return workSheetExtracts, nil
+}
+
+// archive holds the files of an XLSX zip archive, the work
+// sheets listed in its workbook, and the cellReader for the
+// shared strings and styles of the workbook.
+type archive struct {
+	zipFiles   map[string]*zip.File
+	sheets     []sheetInfo
+	cellReader *cellReader
+}
+
+// openArchive opens the zipped XLSX data of the given size,
+// and reads the files shared by its work sheets.
+func openArchive(r io.ReaderAt, size int64, config Config) (*archive, error) {
+	zr, err := zip.NewReader(r, size)
 	if err != nil {
 		return nil, err
 	}
@@ -80,44 +226,100 @@ func Extract(file *os.File, config Config) ([]WorkSheetExtract, error) {
 		}
 	}
 
-	workSheetExtracts := make([]WorkSheetExtract, 0)
-	stringLookup := MakeStringLookup(sharedStrings)
+	sheets, date1904, err := readWorkbook(zipFiles, fileDesc)
+	if err != nil {
+		return nil, err
+	}
+	formats, err := readStyles(zipFiles, fileDesc)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, or := range fileDesc.Overrides {
-		switch or.ContentType {
-		case fileTypeWorkSheet:
-			workSheet, ok := zipFiles[or.PartName]
-			if !ok {
-				return nil, fmt.Errorf("missing work sheet %v", or.PartName)
-			}
-			var sheet WorkSheet
-			if err := decodeZipFile(workSheet, &sheet); err != nil {
-				return nil, fmt.Errorf("could not decode file %v: %v", or.PartName, err)
-			}
+	cellReader := newCellReader(MakeStringLookup(sharedStrings), config)
+	cellReader.formats = formats
+	cellReader.date1904 = date1904
 
-			textMatrix, err := MakeTextMatrix(sheet, stringLookup)
-			if err != nil {
-				return nil, fmt.Errorf("could not create text table: %v", err)
-			}
+	return &archive{
+		zipFiles:   zipFiles,
+		sheets:     sheets,
+		cellReader: cellReader,
+	}, nil
+}
 
-			ttf := texttable.New(textMatrix, texttable.Config{
-				ColumnMargin: config.ColumnMargin,
-				ColumnWidth:  config.ColumnWidth,
-				RowMargin:    config.RowMargin,
-			})
-			stringTable, err := ttf.Output()
-			if err != nil {
-				return nil, fmt.Errorf("could not format text table into string: %v", err)
+// extracts reports whether the work sheet is extracted, being
+// selected by the config, and not a hidden sheet which the
+// config excludes.
+func (config Config) extracts(sheet sheetInfo) (bool, error) {
+	if sheet.state != sheetStateVisible && config.Hidden == HiddenExclude {
+		return false, nil
+	}
+	return config.selects(sheet)
+}
+
+// tableConfig returns the texttable Config for a work sheet,
+// which is width columns wide before any are excluded.
+func (config Config) tableConfig(sheet WorkSheet, info sheetInfo, width int) (texttable.Config, error) {
+	tableConfig := texttable.Config{
+		ColumnMargin: config.ColumnMargin,
+		ColumnWidth:  config.ColumnWidth,
+		RowMargin:    config.RowMargin,
+	}
+	if config.SheetColumnWidths {
+		var excluded columnRanges
+		if config.Hidden == HiddenExclude {
+			var err error
+			if excluded, err = hiddenColumns(sheet); err != nil {
+				return tableConfig, fmt.Errorf("could not hide cells of %v: %v", info.partName, err)
 			}
+		}
+		widths, err := sheetColumnWidths(sheet, width, excluded)
+		if err != nil {
+			return tableConfig, fmt.Errorf("could not read column widths of %v: %v", info.partName, err)
+		}
+		tableConfig.ColumnWidths = scaleColumnWidths(widths, config.TableWidth, config.ColumnMargin)
+	}
+	if config.SheetNameTitle {
+		tableConfig.Title = info.name
+		if info.state != sheetStateVisible && config.Hidden == HiddenMark {
+			tableConfig.Title = config.hiddenMarker() + " " + info.name
+		}
+	}
+	return tableConfig, nil
+}
 
-			workSheetExtracts = append(workSheetExtracts, WorkSheetExtract{
-				SheetName: workSheet.Name,
-				Text:      stringTable,
-			})
+// selects reports whether the work sheet is one of the
+// sheets selected by the config. An error is returned if
+// one of the sheet name patterns is malformed.
+func (config Config) selects(sheet sheetInfo) (bool, error) {
+	if len(config.SheetNames) == 0 && len(config.SheetIndexes) == 0 {
+		return true, nil
+	}
+	for _, index := range config.SheetIndexes {
+		if index == sheet.index {
+			return true, nil
+		}
+	}
+	for _, pattern := range config.SheetNames {
+		if pattern == sheet.name {
+			return true, nil
+		}
+		matched, err := path.Match(pattern, sheet.name)
+		if err != nil {
+			return false, fmt.Errorf("invalid sheet name pattern %v: %v", pattern, err)
+		}
+		if matched {
+			return true, nil
 		}
 	}
+	return false, nil
+}
 
-	return workSheetExtracts, nil
+// hiddenMarker returns the marker written before hidden content.
+func (config Config) hiddenMarker() string {
+	if config.HiddenMarker == "" {
+		return defaultHiddenMarker
+	}
+	return config.HiddenMarker
 }
 
 // SharedStringLookup is a slice of strings, it should be created
@@ -129,48 +331,54 @@ type SharedStringLookup []string
 func MakeStringLookup(sharedStrings SharedStrings) SharedStringLookup {
 	lookupSlice := make(SharedStringLookup, len(sharedStrings.StringItem))
 	for i, si := range sharedStrings.StringItem {
-		if len(si.RichTextRuns) > 0 {
-			text := ""
-			for _, run := range si.RichTextRuns {
-				text += run.Text.Text
-			}
-			lookupSlice[i] += text
-		} else {
-			lookupSlice[i] = si.Text.Text
-		}
+		lookupSlice[i] = si.text()
 	}
 	return lookupSlice
 }
 
+// text returns the text of a string item, joining
+// its rich text runs if it has any.
+func (si stringItem) text() string {
+	if len(si.RichTextRuns) == 0 {
+		return si.Text.Text
+	}
+	text := ""
+	for _, run := range si.RichTextRuns {
+		text += run.Text.Text
+	}
+	return text
+}
+
 // MakeTextMatrix takes a WorkSheet and a SharedStringLookup
 // to create a 2D string slice from. The 2D slice will be populated
-// according to the layout of the WorkSheet.
+// according to the layout of the WorkSheet. Boolean and error
+// cells are written as Excel shows them.
 func MakeTextMatrix(sheet WorkSheet, lookup SharedStringLookup) ([][]string, error) {
+	return makeTextMatrix(sheet, newCellReader(lookup, Config{}))
+}
+
+// makeTextMatrix creates the 2D string slice of a WorkSheet,
+// using the cellReader to write the value of each cell.
+func makeTextMatrix(sheet WorkSheet, reader *cellReader) ([][]string, error) {
 	rows := sheet.SheetData.Rows
 
 	width, height, err := GetSheetSize(sheet)
 	if err != nil {
 		return nil, err
 	}
+	rowYs, cellXs, err := cellPositions(rows)
+	if err != nil {
+		return nil, err
+	}
+
 	textTable := makeStringMatrix(width, height)
-	for _, row := range rows {
-		for _, cell := range row.Cells {
-			x, y, err := parseXYCoordinate(cell.Coordinate)
+	for i, row := range rows {
+		for j, cell := range row.Cells {
+			text, err := reader.text(cell)
 			if err != nil {
-				return nil, fmt.Errorf("could not parse x y index: %v", err)
+				return nil, fmt.Errorf("could not read cell %v: %v", cellName(cellXs[i][j], rowYs[i]), err)
 			}
-
-			var text string
-			if cell.Type == "s" {
-				stringIdx, err := strconv.Atoi(cell.Value)
-				if err != nil {
-					return nil, fmt.Errorf("could not parse string index %v: %v", cell.Value, err)
-				}
-				text = lookup[stringIdx]
-			} else {
-				text = cell.Value
-			}
-			textTable[y][x] = text
+			textTable[rowYs[i]][cellXs[i][j]] = text
 		}
 	}
 
@@ -179,59 +387,155 @@ func MakeTextMatrix(sheet WorkSheet, lookup SharedStringLookup) ([][]string, err
 
 // GetSheetSize takes sheetData and returns
 // the max width and height of the spread sheet
-// cells.
+// cells. The dimension of the sheet is only a hint:
+// if it is usable, see dimensionSize, it is taken as
+// the starting size, which is grown to fit the cells
+// of the sheet. Sheets without rows have a size of 0.
 func GetSheetSize(workSheet WorkSheet) (width, height int, err error) {
-	workSheetRows := workSheet.SheetData.Rows
-
-	// get height
-	lastRow := workSheetRows[len(workSheetRows)-1]
-	pos := lastRow.Cells[0].Coordinate
+	rows := workSheet.SheetData.Rows
+	if len(rows) == 0 {
+		return 0, 0, nil
+	}
+	width, height, _ = dimensionSize(workSheet.Dimension.Ref)
 
-	rowIndexRegex := regexp.MustCompile(`\d+`)
-	matched := rowIndexRegex.Find([]byte(pos))
-	height, err = strconv.Atoi(string(matched))
+	rowYs, cellXs, err := cellPositions(rows)
 	if err != nil {
-		return 0, 0, fmt.Errorf("could not parse height: %v", err)
+		return 0, 0, err
 	}
+	for i := range rows {
+		if rowYs[i] >= height {
+			height = rowYs[i] + 1
+		}
+		for _, x := range cellXs[i] {
+			if x >= width {
+				width = x + 1
+			}
+		}
+	}
+	return width, height, nil
+}
 
-	// get width
-	colIndexRegex := regexp.MustCompile(`[a-zA-Z]+`)
-	maxColIndex := 0
+// maxDimensionCells is the largest number of cells a
+// dimension can cover for its size to be used. Larger
+// dimensions, such as those set by writers which cover
+// the whole sheet, would need far more memory than the
+// cells of the sheet.
+const maxDimensionCells = 1 << 20
+
+// dimensionSize returns the size of a work sheet from its
+// dimension, such as "A1:F20". The rows and columns before
+// the top left cell of the dimension are counted. ok is false
+// if the dimension is missing, is malformed, or covers more
+// than maxDimensionCells cells.
+func dimensionSize(ref string) (width, height int, ok bool) {
+	if ref == "" {
+		return 0, 0, false
+	}
+	last := ref
+	if i := strings.LastIndex(ref, ":"); i >= 0 {
+		last = ref[i+1:]
+	}
+	x, y, err := parseXYCoordinate(last)
+	if err != nil || (x+1)*(y+1) > maxDimensionCells {
+		return 0, 0, false
+	}
+	return x + 1, y + 1, true
+}
 
-	for _, row := range workSheetRows {
-		if rowLength := len(row.Cells); rowLength > 0 {
-			lastCellPos := row.Cells[rowLength-1].Coordinate
-			matched := colIndexRegex.Find([]byte(lastCellPos))
+// cellPositions returns the row index of each of the rows
+// and the column index of each of their cells.
+func cellPositions(rows []row) (rowYs []int, cellXs [][]int, err error) {
+	rowYs = make([]int, len(rows))
+	cellXs = make([][]int, len(rows))
+	y := -1
+	for i, r := range rows {
+		if y, cellXs[i], err = rowPosition(r, y); err != nil {
+			return nil, nil, err
+		}
+		rowYs[i] = y
+	}
+	return rowYs, cellXs, nil
+}
 
-			colIndex := alphaIndex(matched)
-			if colIndex > maxColIndex {
-				maxColIndex = colIndex
-			}
+// rowPosition returns the row index of a row, and the column
+// index of each of its cells. The r attribute of rows and cells
+// is optional: rows without one follow the previous row, and
+// cells without one follow the cell before them, starting from
+// the first column.
+func rowPosition(r row, previous int) (y int, cellXs []int, err error) {
+	y = previous + 1
+	if r.R != "" {
+		n, err := strconv.Atoi(r.R)
+		if err != nil || n < 1 || n > maxRows {
+			return 0, nil, fmt.Errorf("invalid row index %v", r.R)
 		}
+		y = n - 1
 	}
 
-	return maxColIndex + 1, height, nil
+	x := -1
+	cellXs = make([]int, len(r.Cells))
+	for j, c := range r.Cells {
+		x++
+		if c.Coordinate != "" {
+			cx, cy, err := parseXYCoordinate(c.Coordinate)
+			if err != nil {
+				return 0, nil, fmt.Errorf("could not parse x y index: %v", err)
+			}
+			if r.R == "" && j == 0 {
+				y = cy
+			}
+			if cy != y {
+				return 0, nil, fmt.Errorf("cell %v is not in row %v", c.Coordinate, y+1)
+			}
+			x = cx
+		}
+		if x >= maxColumns {
+			return 0, nil, fmt.Errorf("cell %v of row %v is beyond the last column", j+1, y+1)
+		}
+		cellXs[j] = x
+	}
+	return y, cellXs, nil
 }
 
+// The number of rows and columns of a work sheet are limited,
+// to 1048576 rows and 16384 columns, up to column XFD.
+const (
+	maxRows    = 1048576
+	maxColumns = 16384
+)
+
+// xyRegex matches a cell coordinate,
+// such as "A1" or "XFD1048576".
+var xyRegex = regexp.MustCompile(`^([A-Z]+)(\d+)$`)
+
 // parseXYCoordinate takes a XLSX coordinate of the form:
 // AADD, where AA are alphabetical characters and DD are digits.
 // and parses the corresponding int x, y indexes.
 // Example: A1 -> (0, 0) C10 -> (2, 9)
 func parseXYCoordinate(coordinate string) (x, y int, err error) {
-	xyRegex := regexp.MustCompile(`([a-zA-Z]+)(\d+)`)
-	matches := xyRegex.FindSubmatch([]byte(coordinate))
-	if len(matches) != 3 {
+	matches := xyRegex.FindStringSubmatch(strings.ToUpper(coordinate))
+	if len(matches) != 3 || len(matches[1]) > 3 {
 		return 0, 0, fmt.Errorf("invalid coordinate: %v", coordinate)
 	}
-	x = alphaIndex(matches[1])
-	y, err = strconv.Atoi(string(matches[2]))
-	if err != nil {
-		return 0, 0, err
+	x = alphaIndex([]byte(matches[1]))
+	y, err = strconv.Atoi(matches[2])
+	if err != nil || y < 1 || y > maxRows || x >= maxColumns {
+		return 0, 0, fmt.Errorf("invalid coordinate: %v", coordinate)
 	}
 	y = y - 1
 	return x, y, nil
 }
 
+// cellName returns the coordinate of
+// the cell at x, y, such as "C10".
+func cellName(x, y int) string {
+	name := ""
+	for n := x + 1; n > 0; n = (n - 1) / 26 {
+		name = string(alphaPositions[(n-1)%26]) + name
+	}
+	return name + strconv.Itoa(y+1)
+}
+
 // alphaPositions should be used to set the position values
 // of the alphabetical characters
 var alphaPositions = []byte("ABCDEFGHIJKLMNOPQRSTUVWXYZ")
@@ -1,7 +1,10 @@
 package xlsx
 
 import (
+	"encoding/xml"
+	"io/ioutil"
 	"os"
+	"reflect"
 	"testing"
 )
 
@@ -104,9 +107,10 @@ func TestExtract(t *testing.T) {
 			}
 
 			sheets, err := Extract(f, Config{
-				ColumnWidth:  30,
-				ColumnMargin: 2,
-				RowMargin:    1,
+				ColumnWidth:    30,
+				ColumnMargin:   2,
+				RowMargin:      1,
+				SheetNameTitle: true,
 			})
 			if err != nil {
 				t.Fatalf("could not extract text: %v", err)
@@ -115,7 +119,6 @@ func TestExtract(t *testing.T) {
 			text := ""
 
 			for _, sheet := range sheets {
-				text += sheet.SheetName + "\n\n"
 				text += sheet.Text + "\n"
 			}
 
@@ -135,3 +138,327 @@ func TestExtract(t *testing.T) {
 	}
 
 }
+
+func TestExtractBytes(t *testing.T) {
+	inputPath := "./testdata/xlsx_files/two_page_spread_sheet_1/two_page_spread_sheet_1.xlsx"
+	config := Config{ColumnWidth: 30, ColumnMargin: 2, RowMargin: 1}
+
+	f, err := os.Open(inputPath)
+	if err != nil {
+		t.Fatalf("could not open input file: %v", err)
+	}
+	defer f.Close()
+	want, err := Extract(f, config)
+	if err != nil {
+		t.Fatalf("could not extract text: %v", err)
+	}
+
+	data, err := ioutil.ReadFile(inputPath)
+	if err != nil {
+		t.Fatalf("could not read input file: %v", err)
+	}
+	got, err := ExtractBytes(data, config)
+	if err != nil {
+		t.Fatalf("could not extract text from bytes: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected: \n%v\n\ngot: \n%v\n", want, got)
+	}
+
+	if _, err := ExtractBytes([]byte("not a zip file"), config); err == nil {
+		t.Fatalf("expected an error for invalid data")
+	}
+}
+
+func TestExtract_SheetDetails(t *testing.T) {
+	f, err := os.Open("./testdata/xlsx_files/two_page_spread_sheet_1/two_page_spread_sheet_1.xlsx")
+	if err != nil {
+		t.Fatalf("could not open input file: %v", err)
+	}
+	defer f.Close()
+
+	sheets, err := Extract(f, Config{ColumnWidth: 30})
+	if err != nil {
+		t.Fatalf("could not extract text: %v", err)
+	}
+
+	want := []WorkSheetExtract{
+		{SheetName: "Sheet2", SheetID: 2, Index: 0, State: "visible", Path: "xl/worksheets/sheet1.xml"},
+		{SheetName: "Sheet3", SheetID: 3, Index: 1, State: "visible", Path: "xl/worksheets/sheet2.xml"},
+	}
+	if len(sheets) != len(want) {
+		t.Fatalf("expected %v sheets, got %v", len(want), len(sheets))
+	}
+	for i := range sheets {
+		sheets[i].Text = ""
+	}
+	if !reflect.DeepEqual(sheets, want) {
+		t.Fatalf("expected: \n%+v\n\ngot: \n%+v\n", want, sheets)
+	}
+}
+
+func TestExtract_SelectSheets(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		want    []string
+		wantErr bool
+	}{
+		{name: "all sheets", config: Config{}, want: []string{"Sheet2", "Sheet3"}},
+		{name: "by name", config: Config{SheetNames: []string{"Sheet3"}}, want: []string{"Sheet3"}},
+		{name: "by index", config: Config{SheetIndexes: []int{0}}, want: []string{"Sheet2"}},
+		{name: "by pattern", config: Config{SheetNames: []string{"Sheet[23]"}}, want: []string{"Sheet2", "Sheet3"}},
+		{name: "no match", config: Config{SheetNames: []string{"Summary"}}, want: []string{}},
+		{name: "bad pattern", config: Config{SheetNames: []string{"Sheet["}}, wantErr: true},
+	}
+
+	data, err := ioutil.ReadFile("./testdata/xlsx_files/two_page_spread_sheet_1/two_page_spread_sheet_1.xlsx")
+	if err != nil {
+		t.Fatalf("could not read input file: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.ColumnWidth = 30
+			sheets, err := ExtractBytes(data, tt.config)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("could not extract text: %v", err)
+			}
+			names := make([]string, 0)
+			for _, sheet := range sheets {
+				names = append(names, sheet.SheetName)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Fatalf("expected: %v, got: %v", tt.want, names)
+			}
+		})
+	}
+}
+
+func TestMakeTextMatrix_CellTypes(t *testing.T) {
+	sheetXML := `<worksheet><sheetData>
+<row r="1">
+	<c r="A1" t="s"><v>0</v></c>
+	<c r="B1" t="inlineStr"><is><t>inline</t></is></c>
+	<c r="C1" t="inlineStr"><is><r><t>rich </t></r><r><t>text</t></r></is></c>
+	<c r="D1" t="str"><f>UPPER("x")</f><v>X</v></c>
+</row>
+<row r="2">
+	<c r="A2" t="b"><v>1</v></c>
+	<c r="B2" t="b"><v>0</v></c>
+	<c r="C2" t="e"><v>#N/A</v></c>
+	<c r="D2" t="e"><v>#DIV/0!</v></c>
+</row>
+<row r="3">
+	<c r="A3"><v>12.5</v></c>
+	<c r="B3" t="n"><v>3</v></c>
+	<c r="C3" t="d"><v>2019-12-18</v></c>
+	<c r="D3" t="b"><v>true</v></c>
+</row>
+<row r="4">
+	<c r="A4" t="b"><v>yes</v></c>
+	<c r="B4" t="x"><v>raw</v></c>
+</row>
+</sheetData></worksheet>`
+
+	var sheet WorkSheet
+	if err := xml.Unmarshal([]byte(sheetXML), &sheet); err != nil {
+		t.Fatalf("could not decode work sheet: %v", err)
+	}
+	lookup := SharedStringLookup{"shared"}
+
+	tests := []struct {
+		name   string
+		config Config
+		want   [][]string
+	}{
+		{
+			name:   "defaults",
+			config: Config{},
+			want: [][]string{
+				{"shared", "inline", "rich text", "X"},
+				{"TRUE", "FALSE", "#N/A", "#DIV/0!"},
+				{"12.5", "3", "2019-12-18", "TRUE"},
+				{"yes", "raw", "", ""},
+			},
+		},
+		{
+			name: "configured text",
+			config: Config{
+				TrueText:  "yes",
+				FalseText: "no",
+				ErrorText: map[string]string{"#N/A": ""},
+			},
+			want: [][]string{
+				{"shared", "inline", "rich text", "X"},
+				{"yes", "no", "", "#DIV/0!"},
+				{"12.5", "3", "2019-12-18", "yes"},
+				{"yes", "raw", "", ""},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := makeTextMatrix(sheet, newCellReader(lookup, tt.config))
+			if err != nil {
+				t.Fatalf("could not make text matrix: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected: \n%q\n\ngot: \n%q\n", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetSheetSize(t *testing.T) {
+	tests := []struct {
+		name       string
+		sheetXML   string
+		wantWidth  int
+		wantHeight int
+		wantErr    bool
+	}{
+		{
+			name:       "dimension larger than cells",
+			sheetXML:   `<worksheet><dimension ref="B2:D5"/><sheetData><row r="2"><c r="B2"><v>1</v></c></row></sheetData></worksheet>`,
+			wantWidth:  4,
+			wantHeight: 5,
+		},
+		{
+			name:       "inflated dimension",
+			sheetXML:   `<worksheet><dimension ref="A1:XFD1048576"/><sheetData><row r="1"><c r="A1"><v>1</v></c></row></sheetData></worksheet>`,
+			wantWidth:  1,
+			wantHeight: 1,
+		},
+		{
+			name:       "understated dimension",
+			sheetXML:   `<worksheet><dimension ref="A1"/><sheetData><row r="1"><c r="A1"><v>1</v></c><c r="B1"><v>2</v></c></row><row r="2"/></sheetData></worksheet>`,
+			wantWidth:  2,
+			wantHeight: 2,
+		},
+		{
+			name:     "dimension of empty sheet",
+			sheetXML: `<worksheet><dimension ref="A1"/><sheetData/></worksheet>`,
+		},
+		{
+			name:       "scanned",
+			sheetXML:   `<worksheet><sheetData><row r="1"><c r="C1"><v>1</v></c></row><row r="3"><c r="A3"><v>2</v></c></row></sheetData></worksheet>`,
+			wantWidth:  3,
+			wantHeight: 3,
+		},
+		{
+			name:     "empty sheet",
+			sheetXML: `<worksheet><sheetData/></worksheet>`,
+		},
+		{
+			name:       "empty last row",
+			sheetXML:   `<worksheet><sheetData><row r="1"><c r="B1"><v>1</v></c></row><row r="4"/></sheetData></worksheet>`,
+			wantWidth:  2,
+			wantHeight: 4,
+		},
+		{
+			name:       "missing coordinates",
+			sheetXML:   `<worksheet><sheetData><row><c><v>1</v></c><c><v>2</v></c></row><row><c r="D2"><v>3</v></c><c><v>4</v></c></row></sheetData></worksheet>`,
+			wantWidth:  5,
+			wantHeight: 2,
+		},
+		{
+			name:       "malformed dimension",
+			sheetXML:   `<worksheet><dimension ref="A1:9Z"/><sheetData><row r="2"><c r="B2"><v>1</v></c></row></sheetData></worksheet>`,
+			wantWidth:  2,
+			wantHeight: 2,
+		},
+		{
+			name:     "malformed coordinate",
+			sheetXML: `<worksheet><sheetData><row r="1"><c r="1A"><v>1</v></c></row></sheetData></worksheet>`,
+			wantErr:  true,
+		},
+		{
+			name:     "cell in another row",
+			sheetXML: `<worksheet><sheetData><row r="1"><c r="A2"><v>1</v></c></row></sheetData></worksheet>`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sheet WorkSheet
+			if err := xml.Unmarshal([]byte(tt.sheetXML), &sheet); err != nil {
+				t.Fatalf("could not decode work sheet: %v", err)
+			}
+			width, height, err := GetSheetSize(sheet)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got size %vx%v", width, height)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("could not get sheet size: %v", err)
+			}
+			if width != tt.wantWidth || height != tt.wantHeight {
+				t.Fatalf("expected: \n%vx%v\n\ngot: \n%vx%v\n", tt.wantWidth, tt.wantHeight, width, height)
+			}
+		})
+	}
+}
+
+func TestMakeTextMatrix_Positions(t *testing.T) {
+	tests := []struct {
+		name     string
+		sheetXML string
+		want     [][]string
+	}{
+		{
+			name:     "empty sheet",
+			sheetXML: `<worksheet><sheetData/></worksheet>`,
+			want:     [][]string{},
+		},
+		{
+			name:     "missing coordinates",
+			sheetXML: `<worksheet><sheetData><row><c><v>1</v></c><c><v>2</v></c></row><row r="3"><c r="B3"><v>3</v></c><c><v>4</v></c></row></sheetData></worksheet>`,
+			want: [][]string{
+				{"1", "2", ""},
+				{"", "", ""},
+				{"", "3", "4"},
+			},
+		},
+		{
+			name:     "dimension smaller than cells",
+			sheetXML: `<worksheet><dimension ref="A1"/><sheetData><row r="1"><c r="A1"><v>1</v></c><c r="B1"><v>2</v></c></row><row r="2"><c r="A2"><v>3</v></c></row></sheetData></worksheet>`,
+			want: [][]string{
+				{"1", "2"},
+				{"3", ""},
+			},
+		},
+		{
+			name:     "dimension larger than cells",
+			sheetXML: `<worksheet><dimension ref="A1:XFD1048576"/><sheetData><row r="1"><c r="A1"><v>1</v></c></row></sheetData></worksheet>`,
+			want:     [][]string{{"1"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sheet WorkSheet
+			if err := xml.Unmarshal([]byte(tt.sheetXML), &sheet); err != nil {
+				t.Fatalf("could not decode work sheet: %v", err)
+			}
+			got, err := MakeTextMatrix(sheet, nil)
+			if err != nil {
+				t.Fatalf("could not make text matrix: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected: \n%q\n\ngot: \n%q\n", tt.want, got)
+			}
+		})
+	}
+}